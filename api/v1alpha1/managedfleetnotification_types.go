@@ -18,8 +18,10 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // FleetNotification defines the desired spec of ManagedFleetNotification
@@ -30,9 +32,14 @@ type FleetNotification struct {
 	// The summary line of the notification
 	Summary string `json:"summary"`
 
-	// The body text of the notification when the alert is active
+	// The body text of the notification when the alert is active. A Go template when templated is set.
 	NotificationMessage string `json:"notificationMessage"`
 
+	// Whether notificationMessage is a Go template referring to the labels, annotations and times of the alert,
+	// such as {{ .Labels.namespace }}. The message is sent as written when unset, even if it contains {{.
+	// +optional
+	Templated bool `json:"templated,omitempty"`
+
 	// LogType is a categorization property that can be used to group service logs for aggregation and managing notification preferences.
 	LogType string `json:"logType,omitempty"`
 
@@ -48,22 +55,64 @@ type FleetNotification struct {
 
 	// Whether or not limited support should be sent for this notification
 	LimitedSupport bool `json:"limitedSupport,omitempty"`
+
+	// Measured in hours. How long after resendWait has elapsed since the last notification the record of a
+	// hosted cluster is kept before it is removed as stale. Defaults to the operator's stale timeout.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StaleTimeout *int32 `json:"staleTimeout,omitempty"`
+
+	// The maximum number of firing notifications sent to a hosted cluster within the window. Once reached,
+	// the notification is not sent to the hosted cluster again until the oldest send leaves the window,
+	// even if resendWait has elapsed. Must be set along with window.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSendsPerWindow *int32 `json:"maxSendsPerWindow,omitempty"`
+
+	// Measured in hours. The sliding window maxSendsPerWindow applies to. Must be set along with maxSendsPerWindow.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Window *int32 `json:"window,omitempty"`
 }
 
 type ManagedFleetNotificationSpec struct {
 	FleetNotification FleetNotification `json:"fleetNotification"`
 }
 
+// ManagedFleetNotificationStatus summarises the records of the notification across all the
+// ManagedFleetNotificationRecords
+type ManagedFleetNotificationStatus struct {
+	// The number of hosted clusters that currently have a record of the notification
+	HostedClusters int `json:"hostedClusters"`
+
+	// The total number of notifications sent for the alert status firing
+	FiringNotificationSentCount int `json:"firingNotificationSentCount"`
+
+	// The total number of notifications sent for the alert status resolving
+	ResolvedNotificationSentCount int `json:"resolvedNotificationSentCount"`
+
+	// The last time the notification was sent to any hosted cluster
+	LastSentTime *metav1.Time `json:"lastSentTime,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:shortName=mfn
+//+kubebuilder:printcolumn:name="Notification",type=string,JSONPath=`.spec.fleetNotification.name`
+//+kubebuilder:printcolumn:name="Severity",type=string,JSONPath=`.spec.fleetNotification.severity`
+//+kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.status.hostedClusters`
+//+kubebuilder:printcolumn:name="Firing",type=integer,JSONPath=`.status.firingNotificationSentCount`
+//+kubebuilder:printcolumn:name="Resolved",type=integer,JSONPath=`.status.resolvedNotificationSentCount`
+//+kubebuilder:printcolumn:name="Last Sent",type=date,JSONPath=`.status.lastSentTime`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ManagedFleetNotification is the Schema for the managedfleetnotifications API
 type ManagedFleetNotification struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ManagedFleetNotificationSpec `json:"spec,omitempty"`
+	Spec   ManagedFleetNotificationSpec   `json:"spec,omitempty"`
+	Status ManagedFleetNotificationStatus `json:"status,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -87,3 +136,82 @@ func (fn *ManagedFleetNotification) GetNotificationByName(name string) (*Managed
 	}
 	return nil, fmt.Errorf("notification with name %v not found", name)
 }
+
+// SummarizeNotificationRecords returns the status of the notification summarised from the supplied
+// records. A hosted cluster is counted once even when several records hold an item for it.
+func (fn *ManagedFleetNotification) SummarizeNotificationRecords(records []ManagedFleetNotificationRecord) ManagedFleetNotificationStatus {
+	status := ManagedFleetNotificationStatus{}
+	hostedClusters := map[string]bool{}
+	for _, record := range records {
+		for _, rn := range record.Status.NotificationRecordByName {
+			if rn.NotificationName != fn.Spec.FleetNotification.Name {
+				continue
+			}
+			for _, ri := range rn.NotificationRecordItems {
+				hostedClusters[ri.HostedClusterID] = true
+				status.FiringNotificationSentCount += ri.FiringNotificationSentCount
+				status.ResolvedNotificationSentCount += ri.ResolvedNotificationSentCount
+				if ri.LastTransitionTime != nil && (status.LastSentTime == nil || ri.LastTransitionTime.After(status.LastSentTime.Time)) {
+					status.LastSentTime = ri.LastTransitionTime.DeepCopy()
+				}
+			}
+		}
+	}
+	status.HostedClusters = len(hostedClusters)
+	return status
+}
+
+// Validate checks the fleet notification for problems the CRD schema cannot express and
+// returns every problem found. Name uniqueness across ManagedFleetNotifications cannot be
+// checked from a single object and is left to the caller.
+func (fn *ManagedFleetNotification) Validate() field.ErrorList {
+	var allErrs field.ErrorList
+	n := fn.Spec.FleetNotification
+	path := field.NewPath("spec", "fleetNotification")
+
+	if n.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "notification name must not be empty"))
+	}
+
+	if strings.TrimSpace(n.Summary) == "" {
+		allErrs = append(allErrs, field.Required(path.Child("summary"), "notification summary must not be empty"))
+	}
+
+	if n.ResendWait < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("resendWait"), n.ResendWait, "resendWait must not be negative"))
+	}
+
+	if n.StaleTimeout != nil && *n.StaleTimeout < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("staleTimeout"), *n.StaleTimeout, "staleTimeout must not be negative"))
+	}
+
+	if n.MaxSendsPerWindow != nil && *n.MaxSendsPerWindow < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxSendsPerWindow"), *n.MaxSendsPerWindow, "maxSendsPerWindow must be at least 1"))
+	}
+
+	if n.Window != nil && *n.Window < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("window"), *n.Window, "window must be at least 1"))
+	}
+
+	if n.MaxSendsPerWindow != nil && n.Window == nil {
+		allErrs = append(allErrs, field.Required(path.Child("window"), "maxSendsPerWindow and window must be set together"))
+	}
+
+	if n.Window != nil && n.MaxSendsPerWindow == nil {
+		allErrs = append(allErrs, field.Required(path.Child("maxSendsPerWindow"), "maxSendsPerWindow and window must be set together"))
+	}
+
+	if n.Templated {
+		if err := ValidateNotificationTemplate(n.NotificationMessage); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("notificationMessage"), n.NotificationMessage, err.Error()))
+		}
+	}
+
+	for i, ref := range n.References {
+		if err := validateReference(ref); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("references").Index(i), ref, err.Error()))
+		}
+	}
+
+	return allErrs
+}
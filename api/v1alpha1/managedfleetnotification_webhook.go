@@ -0,0 +1,91 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-ocmagent-managed-openshift-io-v1alpha1-managedfleetnotification,mutating=false,failurePolicy=fail,sideEffects=None,groups=ocmagent.managed.openshift.io,resources=managedfleetnotifications,verbs=create;update,versions=v1alpha1,name=vmanagedfleetnotification.ocmagent.managed.openshift.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the ManagedFleetNotification validating webhook with the manager.
+// The API reader is used so that a notification created moments earlier is not missed by a
+// stale cache when checking for name collisions.
+func (fn *ManagedFleetNotification) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, fn).
+		WithValidator(NewManagedFleetNotificationValidator(mgr.GetAPIReader())).
+		Complete()
+}
+
+// NewManagedFleetNotificationValidator returns a validator which uses the reader to look up
+// the other ManagedFleetNotifications in the namespace
+func NewManagedFleetNotificationValidator(reader client.Reader) admission.Validator[*ManagedFleetNotification] {
+	return &managedFleetNotificationValidator{reader: reader}
+}
+
+// managedFleetNotificationValidator rejects ManagedFleetNotifications that OCM Agent could not send
+type managedFleetNotificationValidator struct {
+	reader client.Reader
+}
+
+// ValidateCreate implements admission.Validator
+func (v *managedFleetNotificationValidator) ValidateCreate(ctx context.Context, fn *ManagedFleetNotification) (admission.Warnings, error) {
+	return nil, v.validate(ctx, fn)
+}
+
+// ValidateUpdate implements admission.Validator
+func (v *managedFleetNotificationValidator) ValidateUpdate(ctx context.Context, old, fn *ManagedFleetNotification) (admission.Warnings, error) {
+	if !updateNeedsValidation(fn, old.Spec, fn.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, fn)
+}
+
+// ValidateDelete implements admission.Validator
+func (v *managedFleetNotificationValidator) ValidateDelete(_ context.Context, _ *ManagedFleetNotification) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the notification itself and that no other ManagedFleetNotification in the
+// namespace already uses the same notification name, as alerts are matched to notifications by name
+func (v *managedFleetNotificationValidator) validate(ctx context.Context, fn *ManagedFleetNotification) error {
+	errs := fn.Validate()
+
+	if name := fn.Spec.FleetNotification.Name; name != "" {
+		mfnList := &ManagedFleetNotificationList{}
+		if err := v.reader.List(ctx, mfnList, client.InNamespace(fn.Namespace)); err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		for _, other := range mfnList.Items {
+			if other.Name != fn.Name && other.Spec.FleetNotification.Name == name {
+				errs = append(errs, field.Duplicate(field.NewPath("spec", "fleetNotification", "name"), name))
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("ManagedFleetNotification").GroupKind(), fn.Name, errs)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+)
+
+var _ = Describe("ManagedFleetNotification Webhook", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockClient *clientmocks.MockClient
+		validator  admission.Validator[*v1alpha1.ManagedFleetNotification]
+		testMfn    *v1alpha1.ManagedFleetNotification
+		ctx        = context.TODO()
+	)
+
+	newMfn := func(name, notificationName string) *v1alpha1.ManagedFleetNotification {
+		return &v1alpha1.ManagedFleetNotification{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-ns",
+			},
+			Spec: v1alpha1.ManagedFleetNotificationSpec{
+				FleetNotification: v1alpha1.FleetNotification{
+					Name:                notificationName,
+					Summary:             "Test Summary",
+					NotificationMessage: "Test Message",
+					Severity:            "Info",
+					ResendWait:          24,
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		validator = v1alpha1.NewManagedFleetNotificationValidator(mockClient)
+		testMfn = newMfn("test-mfn", "test-notification")
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("When validating a ManagedFleetNotification", func() {
+		It("accepts a notification with a unique name", func() {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).SetArg(1,
+				v1alpha1.ManagedFleetNotificationList{Items: []v1alpha1.ManagedFleetNotification{
+					*testMfn,
+					*newMfn("other-mfn", "other-notification"),
+				}})
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects a notification name used by another ManagedFleetNotification", func() {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).SetArg(1,
+				v1alpha1.ManagedFleetNotificationList{Items: []v1alpha1.ManagedFleetNotification{
+					*newMfn("other-mfn", "test-notification"),
+				}})
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.fleetNotification.name"))
+		})
+
+		It("rejects a negative resend wait on update", func() {
+			updated := testMfn.DeepCopy()
+			updated.Spec.FleetNotification.ResendWait = -1
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateUpdate(ctx, testMfn, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.fleetNotification.resendWait"))
+		})
+
+		It("accepts an update leaving the spec unchanged of a notification that no longer validates", func() {
+			testMfn.Spec.FleetNotification.ResendWait = -1
+			updated := testMfn.DeepCopy()
+			updated.Finalizers = nil
+			testMfn.Finalizers = []string{"test-finalizer"}
+			_, err := validator.ValidateUpdate(ctx, testMfn, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("accepts any update of a notification being deleted", func() {
+			testMfn.DeletionTimestamp = &metav1.Time{}
+			updated := testMfn.DeepCopy()
+			updated.Spec.FleetNotification.ResendWait = -1
+			_, err := validator.ValidateUpdate(ctx, testMfn, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects a negative stale timeout", func() {
+			staleTimeout := int32(-1)
+			testMfn.Spec.FleetNotification.StaleTimeout = &staleTimeout
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.fleetNotification.staleTimeout"))
+		})
+
+		It("accepts a send limit with a window", func() {
+			maxSends, window := int32(3), int32(24)
+			testMfn.Spec.FleetNotification.MaxSendsPerWindow = &maxSends
+			testMfn.Spec.FleetNotification.Window = &window
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects a send limit without a window", func() {
+			maxSends := int32(3)
+			testMfn.Spec.FleetNotification.MaxSendsPerWindow = &maxSends
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.fleetNotification.window: Required value"))
+		})
+
+		It("rejects a window without a send limit", func() {
+			window := int32(24)
+			testMfn.Spec.FleetNotification.Window = &window
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.fleetNotification.maxSendsPerWindow: Required value"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.fleetNotification.window"))
+		})
+
+		It("rejects a send limit of zero", func() {
+			maxSends, window := int32(0), int32(24)
+			testMfn.Spec.FleetNotification.MaxSendsPerWindow = &maxSends
+			testMfn.Spec.FleetNotification.Window = &window
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.fleetNotification.maxSendsPerWindow"))
+		})
+
+		It("rejects a notification message with an invalid template", func() {
+			testMfn.Spec.FleetNotification.Templated = true
+			testMfn.Spec.FleetNotification.NotificationMessage = "Node {{ .Labels.node is not ready"
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.fleetNotification.notificationMessage"))
+		})
+
+		It("accepts a notification message with template actions when it is not templated", func() {
+			testMfn.Spec.FleetNotification.NotificationMessage = "Node {{ .Labels.node is not ready"
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(nil)
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an internal error when the notifications cannot be listed", func() {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace("test-ns")).Return(fmt.Errorf("fake error"))
+			_, err := validator.ValidateCreate(ctx, testMfn)
+			Expect(apierrors.IsInternalError(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,203 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+)
+
+// notificationRecordItemKey identifies a record item by its notification name and hosted cluster
+type notificationRecordItemKey struct {
+	notificationName string
+	hostedClusterID  string
+}
+
+// NotificationRecordIndex gives constant time access to the notification records of a
+// ManagedFleetNotificationRecord. The pointers it returns point into the status of the record,
+// so changes made through them are kept in the record.
+//
+// The index is only kept up to date by its own methods: build a new one after changing the
+// notification records by other means. Upserting or removing may move the records in memory,
+// so pointers returned before must not be used afterwards.
+// +kubebuilder:object:generate=false
+type NotificationRecordIndex struct {
+	record *ManagedFleetNotificationRecord
+	names  map[string]int
+	items  map[notificationRecordItemKey]int
+}
+
+// NewNotificationRecordIndex builds the index of the notification records of a record. When a
+// notification or a record item is duplicated, the first one is indexed like the lookup helpers do.
+func NewNotificationRecordIndex(fnr *ManagedFleetNotificationRecord) *NotificationRecordIndex {
+	idx := &NotificationRecordIndex{record: fnr}
+	idx.rebuild()
+	return idx
+}
+
+// rebuild indexes the notification records from scratch
+func (idx *NotificationRecordIndex) rebuild() {
+	idx.names = make(map[string]int, len(idx.record.Status.NotificationRecordByName))
+	idx.items = make(map[notificationRecordItemKey]int)
+	for i, rn := range idx.record.Status.NotificationRecordByName {
+		if _, ok := idx.names[rn.NotificationName]; ok {
+			continue
+		}
+		idx.names[rn.NotificationName] = i
+		for j, ri := range rn.NotificationRecordItems {
+			key := notificationRecordItemKey{notificationName: rn.NotificationName, hostedClusterID: ri.HostedClusterID}
+			if _, ok := idx.items[key]; !ok {
+				idx.items[key] = j
+			}
+		}
+	}
+}
+
+// NotificationRecordByName gets the notification records with the given name
+func (idx *NotificationRecordIndex) NotificationRecordByName(notificationName string) (*NotificationRecordByName, bool) {
+	i, ok := idx.names[notificationName]
+	if !ok {
+		return nil, false
+	}
+	return &idx.record.Status.NotificationRecordByName[i], true
+}
+
+// Item gets the record item of a notification for the given hosted cluster
+func (idx *NotificationRecordIndex) Item(notificationName, hostedClusterID string) (*NotificationRecordItem, bool) {
+	i, ok := idx.names[notificationName]
+	if !ok {
+		return nil, false
+	}
+	j, ok := idx.items[notificationRecordItemKey{notificationName: notificationName, hostedClusterID: hostedClusterID}]
+	if !ok {
+		return nil, false
+	}
+	return &idx.record.Status.NotificationRecordByName[i].NotificationRecordItems[j], true
+}
+
+// UpsertNotificationRecordByName adds the notification records with the given name when they do not
+// exist yet, and sets their resend interval
+func (idx *NotificationRecordIndex) UpsertNotificationRecordByName(notificationName string, resendWait int32) *NotificationRecordByName {
+	i, ok := idx.names[notificationName]
+	if !ok {
+		idx.record.Status.NotificationRecordByName = append(idx.record.Status.NotificationRecordByName, NotificationRecordByName{
+			NotificationName:        notificationName,
+			NotificationRecordItems: []NotificationRecordItem{},
+		})
+		i = len(idx.record.Status.NotificationRecordByName) - 1
+		idx.names[notificationName] = i
+	}
+	rn := &idx.record.Status.NotificationRecordByName[i]
+	rn.ResendWait = resendWait
+	return rn
+}
+
+// UpsertItem adds the record item to the notification, or replaces the item of the same hosted cluster
+func (idx *NotificationRecordIndex) UpsertItem(notificationName string, ri NotificationRecordItem) (*NotificationRecordItem, error) {
+	i, ok := idx.names[notificationName]
+	if !ok {
+		return nil, fmt.Errorf("notification %v does not exist", notificationName)
+	}
+	rn := &idx.record.Status.NotificationRecordByName[i]
+	key := notificationRecordItemKey{notificationName: notificationName, hostedClusterID: ri.HostedClusterID}
+	if j, ok := idx.items[key]; ok {
+		rn.NotificationRecordItems[j] = *ri.DeepCopy()
+		return &rn.NotificationRecordItems[j], nil
+	}
+	rn.NotificationRecordItems = append(rn.NotificationRecordItems, *ri.DeepCopy())
+	j := len(rn.NotificationRecordItems) - 1
+	idx.items[key] = j
+	return &rn.NotificationRecordItems[j], nil
+}
+
+// RemoveItemsWhere removes the record items for which remove returns true, keeping the order of the
+// remaining items, and returns the number of items removed
+func (idx *NotificationRecordIndex) RemoveItemsWhere(remove func(notificationName string, ri *NotificationRecordItem) bool) int {
+	removed := 0
+	for i := range idx.record.Status.NotificationRecordByName {
+		rn := &idx.record.Status.NotificationRecordByName[i]
+		kept := rn.NotificationRecordItems[:0]
+		for j := range rn.NotificationRecordItems {
+			if remove(rn.NotificationName, &rn.NotificationRecordItems[j]) {
+				removed++
+				continue
+			}
+			kept = append(kept, rn.NotificationRecordItems[j])
+		}
+		// Clear the tail so the removed items do not stay reachable through the backing array
+		clear(rn.NotificationRecordItems[len(kept):])
+		rn.NotificationRecordItems = kept
+	}
+	if removed > 0 {
+		idx.rebuild()
+	}
+	return removed
+}
+
+// RemoveNotificationRecordByName removes the notification records with the given name, with all their
+// items, and reports whether they existed
+func (idx *NotificationRecordIndex) RemoveNotificationRecordByName(notificationName string) bool {
+	if _, ok := idx.names[notificationName]; !ok {
+		return false
+	}
+	idx.record.Status.NotificationRecordByName = slices.DeleteFunc(idx.record.Status.NotificationRecordByName, func(rn NotificationRecordByName) bool {
+		return rn.NotificationName == notificationName
+	})
+	idx.rebuild()
+	return true
+}
+
+// Compact merges the duplicated notifications into the first one and removes the duplicated record
+// items of a hosted cluster, keeping the most recently sent one. It returns the number of notifications
+// and items removed.
+func (idx *NotificationRecordIndex) Compact() int {
+	removed := 0
+	byName := idx.record.Status.NotificationRecordByName
+	compacted := make([]NotificationRecordByName, 0, len(byName))
+	slots := make(map[string]int, len(byName))
+	for _, rn := range byName {
+		if i, ok := slots[rn.NotificationName]; ok {
+			// Clip the items so appending does not write into the backing array of the record
+			compacted[i].NotificationRecordItems = append(slices.Clip(compacted[i].NotificationRecordItems), rn.NotificationRecordItems...)
+			removed++
+			continue
+		}
+		slots[rn.NotificationName] = len(compacted)
+		compacted = append(compacted, rn)
+	}
+
+	for i := range compacted {
+		items := compacted[i].NotificationRecordItems
+		kept := make([]NotificationRecordItem, 0, len(items))
+		clusters := make(map[string]int, len(items))
+		for _, ri := range items {
+			j, ok := clusters[ri.HostedClusterID]
+			if !ok {
+				clusters[ri.HostedClusterID] = len(kept)
+				kept = append(kept, ri)
+				continue
+			}
+			if sentAfter(ri, kept[j]) {
+				kept[j] = ri
+			}
+			removed++
+		}
+		compacted[i].NotificationRecordItems = kept
+	}
+
+	if removed > 0 {
+		idx.record.Status.NotificationRecordByName = compacted
+		idx.rebuild()
+	}
+	return removed
+}
+
+// sentAfter reports whether the record item a was sent after the record item b.
+// An item that was never sent is considered older than any sent item.
+func sentAfter(a, b NotificationRecordItem) bool {
+	if a.LastTransitionTime == nil {
+		return false
+	}
+	if b.LastTransitionTime == nil {
+		return true
+	}
+	return a.LastTransitionTime.After(b.LastTransitionTime.Time)
+}
@@ -0,0 +1,209 @@
+package v1alpha1_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+var _ = Describe("OCMAgent Controller MFNR Index", func() {
+
+	const (
+		testManagementCluster = "test-mc-id"
+		testNotificationName  = "test-notification-1"
+	)
+
+	var (
+		testMNFR *v1alpha1.ManagedFleetNotificationRecord
+		index    *v1alpha1.NotificationRecordIndex
+		sentAt   time.Time
+	)
+
+	BeforeEach(func() {
+		sentAt = time.Now().Add(-5 * time.Hour)
+		testMNFR = &v1alpha1.ManagedFleetNotificationRecord{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testManagementCluster,
+				Namespace: "openshift-ocm-agent-operator",
+			},
+			Status: v1alpha1.ManagedFleetNotificationRecordStatus{
+				ManagementCluster: testManagementCluster,
+				NotificationRecordByName: []v1alpha1.NotificationRecordByName{
+					{
+						NotificationName: testNotificationName,
+						ResendWait:       1,
+						NotificationRecordItems: []v1alpha1.NotificationRecordItem{
+							{HostedClusterID: "test-hc-1-1"},
+							{HostedClusterID: "test-hc-1-2", FiringNotificationSentCount: 1, LastTransitionTime: &metav1.Time{Time: sentAt}},
+							{HostedClusterID: "test-hc-1-3"},
+						},
+					},
+					{
+						NotificationName: "test-notification-2",
+						ResendWait:       24,
+						NotificationRecordItems: []v1alpha1.NotificationRecordItem{
+							{HostedClusterID: "test-hc-2-1"},
+						},
+					},
+				},
+			},
+		}
+		index = v1alpha1.NewNotificationRecordIndex(testMNFR)
+	})
+
+	Context("Looking up notification records", func() {
+		It("finds the notification records by name", func() {
+			rn, ok := index.NotificationRecordByName("test-notification-2")
+			Expect(ok).To(BeTrue())
+			Expect(rn.ResendWait).To(Equal(int32(24)))
+			_, ok = index.NotificationRecordByName("nope")
+			Expect(ok).To(BeFalse())
+		})
+		It("finds the record items by notification and hosted cluster", func() {
+			ri, ok := index.Item(testNotificationName, "test-hc-1-2")
+			Expect(ok).To(BeTrue())
+			Expect(ri.FiringNotificationSentCount).To(Equal(1))
+			_, ok = index.Item(testNotificationName, "test-hc-2-1")
+			Expect(ok).To(BeFalse())
+			_, ok = index.Item("nope", "test-hc-1-2")
+			Expect(ok).To(BeFalse())
+		})
+		It("returns pointers that write back into the record", func() {
+			rn, _ := index.NotificationRecordByName(testNotificationName)
+			rn.ResendWait = 12
+			ri, _ := index.Item(testNotificationName, "test-hc-1-3")
+			ri.ResolvedNotificationSentCount = 3
+			Expect(testMNFR.Status.NotificationRecordByName[0].ResendWait).To(Equal(int32(12)))
+			Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[2].ResolvedNotificationSentCount).To(Equal(3))
+		})
+	})
+
+	Context("Upserting notification records", func() {
+		It("adds a missing notification", func() {
+			rn := index.UpsertNotificationRecordByName("test-notification-3", 6)
+			Expect(rn.NotificationRecordItems).To(BeEmpty())
+			Expect(testMNFR.Status.NotificationRecordByName).To(HaveLen(3))
+			Expect(testMNFR.Status.NotificationRecordByName[2].ResendWait).To(Equal(int32(6)))
+			_, err := index.UpsertItem("test-notification-3", v1alpha1.NotificationRecordItem{HostedClusterID: "test-hc-3-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testMNFR.Status.NotificationRecordByName[2].NotificationRecordItems).To(HaveLen(1))
+		})
+		It("updates the resend interval of an existing notification", func() {
+			index.UpsertNotificationRecordByName(testNotificationName, 6)
+			Expect(testMNFR.Status.NotificationRecordByName).To(HaveLen(2))
+			Expect(testMNFR.Status.NotificationRecordByName[0].ResendWait).To(Equal(int32(6)))
+			Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems).To(HaveLen(3))
+		})
+	})
+
+	Context("Upserting record items", func() {
+		It("adds a missing item that writes back into the record", func() {
+			ri, err := index.UpsertItem(testNotificationName, v1alpha1.NotificationRecordItem{HostedClusterID: "test-hc-1-4"})
+			Expect(err).NotTo(HaveOccurred())
+			ri.FiringNotificationSentCount = 1
+			items := testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems
+			Expect(items).To(HaveLen(4))
+			Expect(items[3].FiringNotificationSentCount).To(Equal(1))
+			found, ok := index.Item(testNotificationName, "test-hc-1-4")
+			Expect(ok).To(BeTrue())
+			Expect(found).To(BeIdenticalTo(&items[3]))
+		})
+		It("replaces an existing item in place", func() {
+			_, err := index.UpsertItem(testNotificationName, v1alpha1.NotificationRecordItem{HostedClusterID: "test-hc-1-1", FiringNotificationSentCount: 2})
+			Expect(err).NotTo(HaveOccurred())
+			items := testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems
+			Expect(items).To(HaveLen(3))
+			Expect(items[0].FiringNotificationSentCount).To(Equal(2))
+		})
+		It("does not share the last transition time with the caller", func() {
+			ltt := &metav1.Time{Time: sentAt}
+			_, err := index.UpsertItem(testNotificationName, v1alpha1.NotificationRecordItem{HostedClusterID: "test-hc-1-1", LastTransitionTime: ltt})
+			Expect(err).NotTo(HaveOccurred())
+			ltt.Time = time.Now()
+			Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[0].LastTransitionTime.Time).To(Equal(sentAt))
+		})
+		It("errors when the notification does not exist", func() {
+			_, err := index.UpsertItem("nope", v1alpha1.NotificationRecordItem{HostedClusterID: "test-hc-1-1"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Removing record items", func() {
+		It("removes the matching items and keeps the order of the others", func() {
+			removed := index.RemoveItemsWhere(func(_ string, ri *v1alpha1.NotificationRecordItem) bool {
+				return ri.LastTransitionTime == nil
+			})
+			Expect(removed).To(Equal(3))
+			Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems).To(Equal([]v1alpha1.NotificationRecordItem{
+				{HostedClusterID: "test-hc-1-2", FiringNotificationSentCount: 1, LastTransitionTime: &metav1.Time{Time: sentAt}},
+			}))
+			Expect(testMNFR.Status.NotificationRecordByName[1].NotificationRecordItems).To(BeEmpty())
+		})
+		It("keeps the index in step with the record", func() {
+			index.RemoveItemsWhere(func(notificationName string, ri *v1alpha1.NotificationRecordItem) bool {
+				return notificationName == testNotificationName && ri.HostedClusterID == "test-hc-1-1"
+			})
+			_, ok := index.Item(testNotificationName, "test-hc-1-1")
+			Expect(ok).To(BeFalse())
+			ri, ok := index.Item(testNotificationName, "test-hc-1-3")
+			Expect(ok).To(BeTrue())
+			Expect(ri.HostedClusterID).To(Equal("test-hc-1-3"))
+			Expect(ri).To(BeIdenticalTo(&testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1]))
+		})
+	})
+
+	Context("Removing notification records", func() {
+		It("removes the notification with its items", func() {
+			Expect(index.RemoveNotificationRecordByName(testNotificationName)).To(BeTrue())
+			Expect(testMNFR.Status.NotificationRecordByName).To(HaveLen(1))
+			Expect(testMNFR.Status.NotificationRecordByName[0].NotificationName).To(Equal("test-notification-2"))
+			_, ok := index.Item(testNotificationName, "test-hc-1-1")
+			Expect(ok).To(BeFalse())
+			ri, ok := index.Item("test-notification-2", "test-hc-2-1")
+			Expect(ok).To(BeTrue())
+			Expect(ri).To(BeIdenticalTo(&testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[0]))
+		})
+		It("reports a missing notification", func() {
+			Expect(index.RemoveNotificationRecordByName("nope")).To(BeFalse())
+			Expect(testMNFR.Status.NotificationRecordByName).To(HaveLen(2))
+		})
+	})
+
+	Context("Compacting the record", func() {
+		It("merges duplicated notifications and keeps the most recently sent duplicated item", func() {
+			testMNFR.Status.NotificationRecordByName = append(testMNFR.Status.NotificationRecordByName, v1alpha1.NotificationRecordByName{
+				NotificationName: testNotificationName,
+				ResendWait:       1,
+				NotificationRecordItems: []v1alpha1.NotificationRecordItem{
+					{HostedClusterID: "test-hc-1-1", FiringNotificationSentCount: 4, LastTransitionTime: &metav1.Time{Time: sentAt}},
+					{HostedClusterID: "test-hc-1-2", FiringNotificationSentCount: 9, LastTransitionTime: &metav1.Time{Time: sentAt.Add(-time.Hour)}},
+					{HostedClusterID: "test-hc-1-4"},
+				},
+			})
+			original := testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems
+			index = v1alpha1.NewNotificationRecordIndex(testMNFR)
+
+			Expect(index.Compact()).To(Equal(3))
+			Expect(testMNFR.Status.NotificationRecordByName).To(HaveLen(2))
+			items := testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems
+			Expect(items).To(HaveLen(4))
+			Expect(items[0].FiringNotificationSentCount).To(Equal(4))
+			Expect(items[1].FiringNotificationSentCount).To(Equal(1))
+			Expect(items[3].HostedClusterID).To(Equal("test-hc-1-4"))
+			Expect(original[0].FiringNotificationSentCount).To(Equal(0))
+
+			ri, ok := index.Item(testNotificationName, "test-hc-1-4")
+			Expect(ok).To(BeTrue())
+			Expect(ri).To(BeIdenticalTo(&items[3]))
+		})
+		It("leaves a record without duplicates untouched", func() {
+			before := testMNFR.DeepCopy()
+			Expect(index.Compact()).To(Equal(0))
+			Expect(testMNFR).To(Equal(before))
+		})
+	})
+})
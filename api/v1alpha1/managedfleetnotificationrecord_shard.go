@@ -0,0 +1,161 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A ManagedFleetNotificationRecord used to hold the records of every notification sent for a management
+// cluster, which grows with the number of hosted clusters times the number of notifications. The records
+// are now sharded, with one ManagedFleetNotificationRecord per management cluster and notification name,
+// each holding a single NotificationRecordByName. The helpers below hide the sharding from the callers.
+const (
+	// ManagementClusterLabel is set on the record shards to the management cluster they belong to
+	ManagementClusterLabel = "ocmagent.managed.openshift.io/management-cluster"
+	// NotificationNameHashLabel is set on the record shards to the hash of the notification name they hold
+	NotificationNameHashLabel = "ocmagent.managed.openshift.io/notification-name-hash"
+)
+
+// notificationNameHash returns a hash of a notification name that can be used in object names and labels
+func notificationNameHash(notificationName string) string {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(notificationName))
+	return fmt.Sprintf("%08x", hasher.Sum32())
+}
+
+// NotificationRecordShardName returns the name of the record shard of a notification for a management cluster
+func NotificationRecordShardName(mc, notificationName string) string {
+	return fmt.Sprintf("%s-%s", mc, notificationNameHash(notificationName))
+}
+
+// NewNotificationRecordShard returns the record shard holding the records of a notification for a management cluster
+func NewNotificationRecordShard(namespace, mc string, rn NotificationRecordByName) *ManagedFleetNotificationRecord {
+	return &ManagedFleetNotificationRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NotificationRecordShardName(mc, rn.NotificationName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				ManagementClusterLabel:    mc,
+				NotificationNameHashLabel: notificationNameHash(rn.NotificationName),
+			},
+		},
+		Status: ManagedFleetNotificationRecordStatus{
+			ManagementCluster:        mc,
+			NotificationRecordByName: []NotificationRecordByName{*rn.DeepCopy()},
+		},
+	}
+}
+
+// IsNotificationRecordShard reports whether the record is a shard holding the records of a single notification
+func (fnr *ManagedFleetNotificationRecord) IsNotificationRecordShard() bool {
+	_, ok := fnr.Labels[NotificationNameHashLabel]
+	return ok
+}
+
+// SplitNotificationRecord returns the record shards holding the records of each notification of an unsharded record
+func (fnr *ManagedFleetNotificationRecord) SplitNotificationRecord() []*ManagedFleetNotificationRecord {
+	shards := make([]*ManagedFleetNotificationRecord, 0, len(fnr.Status.NotificationRecordByName))
+	for _, rn := range fnr.Status.NotificationRecordByName {
+		shards = append(shards, NewNotificationRecordShard(fnr.Namespace, fnr.Status.ManagementCluster, rn))
+	}
+	return shards
+}
+
+// MergeNotificationRecordByName adds the items of the supplied notification records that are not in the
+// record yet, and reports whether the record was changed. An item in both is replaced by the supplied one
+// when that one was sent last, otherwise the item already in the record is kept as it is.
+func (fnr *ManagedFleetNotificationRecord) MergeNotificationRecordByName(rn NotificationRecordByName) bool {
+	for i := range fnr.Status.NotificationRecordByName {
+		existing := &fnr.Status.NotificationRecordByName[i]
+		if existing.NotificationName != rn.NotificationName {
+			continue
+		}
+		known := make(map[string]int, len(existing.NotificationRecordItems))
+		for j, ri := range existing.NotificationRecordItems {
+			known[ri.HostedClusterID] = j
+		}
+		changed := false
+		for _, ri := range rn.NotificationRecordItems {
+			j, ok := known[ri.HostedClusterID]
+			switch {
+			case !ok:
+				existing.NotificationRecordItems = append(existing.NotificationRecordItems, *ri.DeepCopy())
+				changed = true
+			case sentAfter(ri, existing.NotificationRecordItems[j]):
+				existing.NotificationRecordItems[j] = *ri.DeepCopy()
+				changed = true
+			}
+		}
+		return changed
+	}
+	fnr.Status.NotificationRecordByName = append(fnr.Status.NotificationRecordByName, *rn.DeepCopy())
+	return true
+}
+
+// GetNotificationRecordForName gets the record holding the records of a notification for a management cluster.
+// The record shard of the notification is returned when it exists, otherwise the unsharded record of the
+// management cluster that has not been migrated yet. A NotFound error is returned when neither exists.
+func GetNotificationRecordForName(ctx context.Context, c client.Reader, namespace, mc, notificationName string) (*ManagedFleetNotificationRecord, error) {
+	shard := &ManagedFleetNotificationRecord{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: NotificationRecordShardName(mc, notificationName)}, shard)
+	if err == nil {
+		return shard, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	record := &ManagedFleetNotificationRecord{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: mc}, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetOrCreateNotificationRecordShard gets the record shard holding the records of a notification for a
+// management cluster, creating it from the supplied notification records when it does not exist. Unlike
+// GetNotificationRecordForName it never returns the unsharded record, so the records written to it are
+// always kept in the shard of the notification.
+func GetOrCreateNotificationRecordShard(ctx context.Context, c client.Client, namespace, mc string, rn NotificationRecordByName) (*ManagedFleetNotificationRecord, error) {
+	shard := NewNotificationRecordShard(namespace, mc, rn)
+	existing := &ManagedFleetNotificationRecord{}
+	err := c.Get(ctx, client.ObjectKeyFromObject(shard), existing)
+	if err == nil {
+		return existing, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	// The status is not set when the record is created, so it is written separately
+	status := shard.Status
+	if err := c.Create(ctx, shard); err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(shard), existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	shard.Status = status
+	if err := c.Status().Update(ctx, shard); err != nil {
+		return nil, err
+	}
+	return shard, nil
+}
+
+// ListNotificationRecordShards lists the record shards of a management cluster
+func ListNotificationRecordShards(ctx context.Context, c client.Reader, namespace, mc string) ([]ManagedFleetNotificationRecord, error) {
+	records := &ManagedFleetNotificationRecordList{}
+	if err := c.List(ctx, records, client.InNamespace(namespace), client.MatchingLabels{ManagementClusterLabel: mc}); err != nil {
+		return nil, err
+	}
+	return records.Items, nil
+}
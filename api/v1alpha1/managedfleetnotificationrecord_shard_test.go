@@ -0,0 +1,260 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+)
+
+var _ = Describe("OCMAgent Controller MFNR Shards", func() {
+
+	const (
+		testNamespace         = "openshift-ocm-agent-operator"
+		testManagementCluster = "test-mc-id"
+	)
+
+	var (
+		testMNFR *v1alpha1.ManagedFleetNotificationRecord
+	)
+
+	BeforeEach(func() {
+		testMNFR = &v1alpha1.ManagedFleetNotificationRecord{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testManagementCluster,
+				Namespace: testNamespace,
+			},
+			Status: v1alpha1.ManagedFleetNotificationRecordStatus{
+				ManagementCluster: testManagementCluster,
+				NotificationRecordByName: []v1alpha1.NotificationRecordByName{
+					{
+						NotificationName: "Notification A",
+						ResendWait:       1,
+						NotificationRecordItems: []v1alpha1.NotificationRecordItem{
+							{HostedClusterID: "hc-1", FiringNotificationSentCount: 1},
+						},
+					},
+					{
+						NotificationName: "notification_b",
+						ResendWait:       24,
+					},
+				},
+			},
+		}
+	})
+
+	Context("Naming a record shard", func() {
+		It("returns a stable valid object name per management cluster and notification", func() {
+			name := v1alpha1.NotificationRecordShardName(testManagementCluster, "Notification A")
+			Expect(name).To(Equal(v1alpha1.NotificationRecordShardName(testManagementCluster, "Notification A")))
+			Expect(name).To(HavePrefix(testManagementCluster + "-"))
+			Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+			Expect(name).NotTo(Equal(v1alpha1.NotificationRecordShardName(testManagementCluster, "notification_b")))
+			Expect(name).NotTo(Equal(v1alpha1.NotificationRecordShardName("other-mc-id", "Notification A")))
+		})
+	})
+
+	Context("Splitting an unsharded record", func() {
+		It("returns one labelled shard per notification", func() {
+			shards := testMNFR.SplitNotificationRecord()
+			Expect(shards).To(HaveLen(2))
+			for i, shard := range shards {
+				rn := testMNFR.Status.NotificationRecordByName[i]
+				Expect(shard.Name).To(Equal(v1alpha1.NotificationRecordShardName(testManagementCluster, rn.NotificationName)))
+				Expect(shard.Namespace).To(Equal(testNamespace))
+				Expect(shard.Labels).To(HaveKeyWithValue(v1alpha1.ManagementClusterLabel, testManagementCluster))
+				Expect(shard.IsNotificationRecordShard()).To(BeTrue())
+				Expect(shard.Status.ManagementCluster).To(Equal(testManagementCluster))
+				Expect(shard.Status.NotificationRecordByName).To(Equal([]v1alpha1.NotificationRecordByName{rn}))
+			}
+			Expect(testMNFR.IsNotificationRecordShard()).To(BeFalse())
+		})
+		It("keeps the existing record helpers working on a shard", func() {
+			shard := testMNFR.SplitNotificationRecord()[0]
+			Expect(shard.HasNotificationRecordItem(testManagementCluster, "Notification A", "hc-1")).To(BeTrue())
+		})
+	})
+
+	Context("Merging notification records", func() {
+		It("adds the missing items and keeps the existing ones", func() {
+			changed := testMNFR.MergeNotificationRecordByName(v1alpha1.NotificationRecordByName{
+				NotificationName: "Notification A",
+				NotificationRecordItems: []v1alpha1.NotificationRecordItem{
+					{HostedClusterID: "hc-1", FiringNotificationSentCount: 7},
+					{HostedClusterID: "hc-2"},
+				},
+			})
+			Expect(changed).To(BeTrue())
+			items := testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems
+			Expect(items).To(HaveLen(2))
+			Expect(items[0].FiringNotificationSentCount).To(Equal(1))
+			Expect(items[1].HostedClusterID).To(Equal("hc-2"))
+		})
+		It("replaces an existing item with one sent after it", func() {
+			sent := metav1.NewTime(time.Date(2026, time.October, 14, 12, 30, 0, 0, time.UTC))
+			testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[0].LastTransitionTime = &sent
+			later := metav1.NewTime(sent.Add(time.Hour))
+			earlier := metav1.NewTime(sent.Add(-time.Hour))
+
+			changed := testMNFR.MergeNotificationRecordByName(v1alpha1.NotificationRecordByName{
+				NotificationName: "Notification A",
+				NotificationRecordItems: []v1alpha1.NotificationRecordItem{
+					{HostedClusterID: "hc-1", FiringNotificationSentCount: 3, LastTransitionTime: &earlier},
+				},
+			})
+			Expect(changed).To(BeFalse())
+			Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[0].FiringNotificationSentCount).To(Equal(1))
+
+			changed = testMNFR.MergeNotificationRecordByName(v1alpha1.NotificationRecordByName{
+				NotificationName: "Notification A",
+				NotificationRecordItems: []v1alpha1.NotificationRecordItem{
+					{HostedClusterID: "hc-1", FiringNotificationSentCount: 2, LastTransitionTime: &later},
+				},
+			})
+			Expect(changed).To(BeTrue())
+			items := testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems
+			Expect(items).To(HaveLen(1))
+			Expect(items[0].FiringNotificationSentCount).To(Equal(2))
+			Expect(items[0].LastTransitionTime.Time).To(Equal(later.Time))
+		})
+		It("reports no change when every item is known", func() {
+			changed := testMNFR.MergeNotificationRecordByName(v1alpha1.NotificationRecordByName{
+				NotificationName:        "Notification A",
+				NotificationRecordItems: []v1alpha1.NotificationRecordItem{{HostedClusterID: "hc-1"}},
+			})
+			Expect(changed).To(BeFalse())
+		})
+		It("adds an unknown notification", func() {
+			changed := testMNFR.MergeNotificationRecordByName(v1alpha1.NotificationRecordByName{NotificationName: "notification_c"})
+			Expect(changed).To(BeTrue())
+			Expect(testMNFR.Status.NotificationRecordByName).To(HaveLen(3))
+		})
+	})
+
+	Context("Getting the record of a notification", func() {
+		var (
+			mockCtrl   *gomock.Controller
+			mockClient *clientmocks.MockClient
+			ctx        = context.TODO()
+			shardKey   types.NamespacedName
+			legacyKey  types.NamespacedName
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockClient = clientmocks.NewMockClient(mockCtrl)
+			shardKey = types.NamespacedName{Namespace: testNamespace, Name: v1alpha1.NotificationRecordShardName(testManagementCluster, "Notification A")}
+			legacyKey = types.NamespacedName{Namespace: testNamespace, Name: testManagementCluster}
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("returns the shard of the notification", func() {
+			shard := testMNFR.SplitNotificationRecord()[0]
+			mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).SetArg(2, *shard)
+			record, err := v1alpha1.GetNotificationRecordForName(ctx, mockClient, testNamespace, testManagementCluster, "Notification A")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Name).To(Equal(shardKey.Name))
+		})
+		It("falls back to the unsharded record of the management cluster", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, shardKey.Name)),
+				mockClient.EXPECT().Get(gomock.Any(), legacyKey, gomock.Any()).SetArg(2, *testMNFR),
+			)
+			record, err := v1alpha1.GetNotificationRecordForName(ctx, mockClient, testNamespace, testManagementCluster, "Notification A")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Name).To(Equal(testManagementCluster))
+		})
+		It("returns NotFound when there is no record", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, shardKey.Name)),
+				mockClient.EXPECT().Get(gomock.Any(), legacyKey, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, legacyKey.Name)),
+			)
+			_, err := v1alpha1.GetNotificationRecordForName(ctx, mockClient, testNamespace, testManagementCluster, "Notification A")
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+		It("returns other errors without falling back", func() {
+			mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).Return(fmt.Errorf("fake error"))
+			_, err := v1alpha1.GetNotificationRecordForName(ctx, mockClient, testNamespace, testManagementCluster, "Notification A")
+			Expect(err).To(MatchError("fake error"))
+		})
+	})
+
+	Context("Getting or creating the record shard of a notification", func() {
+		var (
+			mockCtrl         *gomock.Controller
+			mockClient       *clientmocks.MockClient
+			mockStatusWriter *clientmocks.MockStatusWriter
+			ctx              = context.TODO()
+			rn               v1alpha1.NotificationRecordByName
+			shardKey         types.NamespacedName
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockClient = clientmocks.NewMockClient(mockCtrl)
+			mockStatusWriter = clientmocks.NewMockStatusWriter(mockCtrl)
+			rn = testMNFR.Status.NotificationRecordByName[0]
+			shardKey = types.NamespacedName{Namespace: testNamespace, Name: v1alpha1.NotificationRecordShardName(testManagementCluster, rn.NotificationName)}
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("returns the existing shard of the notification", func() {
+			shard := testMNFR.SplitNotificationRecord()[0]
+			shard.ResourceVersion = "3"
+			mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).SetArg(2, *shard)
+			mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			record, err := v1alpha1.GetOrCreateNotificationRecordShard(ctx, mockClient, testNamespace, testManagementCluster, rn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.ResourceVersion).To(Equal("3"))
+		})
+		It("creates the shard with its records rather than using the unsharded record", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, shardKey.Name)),
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, o *v1alpha1.ManagedFleetNotificationRecord, opts ...client.CreateOption) error {
+						Expect(o.Name).To(Equal(shardKey.Name))
+						Expect(o.IsNotificationRecordShard()).To(BeTrue())
+						o.Status = v1alpha1.ManagedFleetNotificationRecordStatus{}
+						return nil
+					}),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil),
+			)
+			record, err := v1alpha1.GetOrCreateNotificationRecordShard(ctx, mockClient, testNamespace, testManagementCluster, rn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Name).To(Equal(shardKey.Name))
+			Expect(record.Status.ManagementCluster).To(Equal(testManagementCluster))
+			Expect(record.Status.NotificationRecordByName).To(Equal([]v1alpha1.NotificationRecordByName{rn}))
+		})
+		It("returns the shard created concurrently", func() {
+			shard := testMNFR.SplitNotificationRecord()[0]
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, shardKey.Name)),
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(apierrors.NewAlreadyExists(schema.GroupResource{}, shardKey.Name)),
+				mockClient.EXPECT().Get(gomock.Any(), shardKey, gomock.Any()).SetArg(2, *shard),
+			)
+			record, err := v1alpha1.GetOrCreateNotificationRecordShard(ctx, mockClient, testNamespace, testManagementCluster, rn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Name).To(Equal(shardKey.Name))
+		})
+	})
+})
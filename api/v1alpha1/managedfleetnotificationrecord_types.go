@@ -21,6 +21,7 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
 )
 
 // ManagedFleetNotificationRecordStatus defines the observed state of ManagedFleetNotificationRecord
@@ -37,6 +38,12 @@ type NotificationRecordByName struct {
 	NotificationName string `json:"notificationName"`
 	// Resend interval for the notification
 	ResendWait int32 `json:"resendWait"`
+	// The maximum number of firing notifications sent to a hosted cluster within the window
+	// +optional
+	MaxSendsPerWindow *int32 `json:"maxSendsPerWindow,omitempty"`
+	// Measured in hours. The sliding window maxSendsPerWindow applies to
+	// +optional
+	Window *int32 `json:"window,omitempty"`
 	// Notification record item with the notification name
 	NotificationRecordItems []NotificationRecordItem `json:"notificationRecordItems"`
 }
@@ -54,6 +61,11 @@ type NotificationRecordItem struct {
 
 	// The last notification sent timestamp
 	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// The times notifications were sent for the alert status firing within the window, oldest first.
+	// Only kept when the notification limits the sends per window.
+	// +optional
+	RecentSendTimes []metav1.Time `json:"recentSendTimes,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -90,30 +102,33 @@ func (fnr *ManagedFleetNotificationRecord) GetNotificationRecordByMC(mc string)
 	return fnr, nil
 }
 
+// GetNotificationRecordByName gets the notification records with the given name. The returned
+// pointer points into the status of the record, so changes made through it are kept.
 func (fnr *ManagedFleetNotificationRecord) GetNotificationRecordByName(mc, name string) (*NotificationRecordByName, error) {
 	r, err := fnr.GetNotificationRecordByMC(mc)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, rn := range r.Status.NotificationRecordByName {
-		if rn.NotificationName == name {
-			return &rn, nil
+	for i := range r.Status.NotificationRecordByName {
+		if r.Status.NotificationRecordByName[i].NotificationName == name {
+			return &r.Status.NotificationRecordByName[i], nil
 		}
 	}
 	return nil, fmt.Errorf("cannot find notification record for name %s", name)
 }
 
-// GetNotificationRecordItem gets the record item for the specified hosted cluster
+// GetNotificationRecordItem gets the record item for the specified hosted cluster. The returned
+// pointer points into the status of the record, so changes made through it are kept.
 func (fnr *ManagedFleetNotificationRecord) GetNotificationRecordItem(mc, name, clusterID string) (*NotificationRecordItem, error) {
 	rn, err := fnr.GetNotificationRecordByName(mc, name)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, ri := range rn.NotificationRecordItems {
-		if ri.HostedClusterID == clusterID {
-			return &ri, nil
+	for i := range rn.NotificationRecordItems {
+		if rn.NotificationRecordItems[i].HostedClusterID == clusterID {
+			return &rn.NotificationRecordItems[i], nil
 		}
 	}
 
@@ -143,6 +158,11 @@ func (fnr *ManagedFleetNotificationRecord) HasNotificationRecordItem(mc, name, c
 
 // FiringCanBeSent checks if the notification can be sent for a firing alert for the given hosted cluster
 func (fnr *ManagedFleetNotificationRecord) FiringCanBeSent(mc, name, clusterID string) (bool, error) {
+	return fnr.FiringCanBeSentWithClock(clock.RealClock{}, mc, name, clusterID)
+}
+
+// FiringCanBeSentWithClock is FiringCanBeSent with the current time told by the given clock
+func (fnr *ManagedFleetNotificationRecord) FiringCanBeSentWithClock(clk clock.PassiveClock, mc, name, clusterID string) (bool, error) {
 	rn, err := fnr.GetNotificationRecordByName(mc, name)
 	if err != nil {
 		return false, err
@@ -165,16 +185,93 @@ func (fnr *ManagedFleetNotificationRecord) FiringCanBeSent(mc, name, clusterID s
 		return true, nil
 	}
 
+	now := clk.Now()
 	nextSend := ri.LastTransitionTime.Add(time.Duration(interval) * time.Hour)
 
-	if time.Now().After(nextSend) {
-		return true, nil
+	if now.After(nextSend) {
+		return rn.withinSendLimit(ri, now), nil
 	}
 
 	return false, nil
 }
 
-// AddNotificationRecordItem adds a new record item to the notification record slice
+// HasFleetNotificationSettings reports whether the notification records hold the settings of the
+// fleet notification they depend on
+func (rn *NotificationRecordByName) HasFleetNotificationSettings(n FleetNotification) bool {
+	return rn.ResendWait == n.ResendWait &&
+		equalInt32Ptr(rn.MaxSendsPerWindow, n.MaxSendsPerWindow) &&
+		equalInt32Ptr(rn.Window, n.Window)
+}
+
+// SetFleetNotificationSettings copies the settings of the fleet notification the records depend on
+func (rn *NotificationRecordByName) SetFleetNotificationSettings(n FleetNotification) {
+	rn.ResendWait = n.ResendWait
+	rn.MaxSendsPerWindow = copyInt32Ptr(n.MaxSendsPerWindow)
+	rn.Window = copyInt32Ptr(n.Window)
+}
+
+// sendLimit returns the maximum number of firing notifications sent within the window,
+// or false when the notification does not limit them
+func (rn *NotificationRecordByName) sendLimit() (int, time.Duration, bool) {
+	if rn.MaxSendsPerWindow == nil || rn.Window == nil {
+		return 0, 0, false
+	}
+	return int(*rn.MaxSendsPerWindow), time.Duration(*rn.Window) * time.Hour, true
+}
+
+// withinSendLimit reports whether another firing notification can be sent for the record item
+// without exceeding the sends per window
+func (rn *NotificationRecordByName) withinSendLimit(ri *NotificationRecordItem, now time.Time) bool {
+	maxSends, window, ok := rn.sendLimit()
+	if !ok {
+		return true
+	}
+	return len(sendsWithin(ri.RecentSendTimes, now, window)) < maxSends
+}
+
+// recordSend returns the recent send times of a record item with a send at the given time added.
+// Sends that left the window are dropped, and only as many as the limit are kept.
+func (rn *NotificationRecordByName) recordSend(ri *NotificationRecordItem, now time.Time) []metav1.Time {
+	maxSends, window, ok := rn.sendLimit()
+	if !ok {
+		return nil
+	}
+	sends := append(sendsWithin(ri.RecentSendTimes, now, window), metav1.Time{Time: now})
+	if len(sends) > maxSends {
+		sends = sends[len(sends)-maxSends:]
+	}
+	return sends
+}
+
+// sendsWithin returns the send times that are within the window ending at the given time
+func sendsWithin(sends []metav1.Time, now time.Time, window time.Duration) []metav1.Time {
+	start := now.Add(-window)
+	within := make([]metav1.Time, 0, len(sends))
+	for _, sent := range sends {
+		if sent.After(start) {
+			within = append(within, sent)
+		}
+	}
+	return within
+}
+
+func equalInt32Ptr(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func copyInt32Ptr(p *int32) *int32 {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// AddNotificationRecordItem adds a new record item to the notification record slice. The returned
+// pointer points to the item added to the status of the record.
 func (fnr *ManagedFleetNotificationRecord) AddNotificationRecordItem(clusterID string, rn *NotificationRecordByName) (*NotificationRecordItem, error) {
 	for i, nfr := range fnr.Status.NotificationRecordByName {
 		if nfr.NotificationName != rn.NotificationName {
@@ -191,28 +288,39 @@ func (fnr *ManagedFleetNotificationRecord) AddNotificationRecordItem(clusterID s
 			HostedClusterID:               clusterID,
 			LastTransitionTime:            nil,
 		}
-		fnr.Status.NotificationRecordByName[i].NotificationRecordItems = append(fnr.Status.NotificationRecordByName[i].NotificationRecordItems, ri)
-		return &ri, nil
+		items := append(fnr.Status.NotificationRecordByName[i].NotificationRecordItems, ri)
+		fnr.Status.NotificationRecordByName[i].NotificationRecordItems = items
+		return &items[len(items)-1], nil
 	}
 	return nil, fmt.Errorf("notification %v does not exist", rn.NotificationName)
 }
 
-// UpdateNotificationRecordItem updates the notification sent count and timestamp for the last time sent
+// UpdateNotificationRecordItem updates the notification sent count and timestamp for the last time sent,
+// and the recent firing sends when the notification limits the sends per window
 func (fnr *ManagedFleetNotificationRecord) UpdateNotificationRecordItem(notificationName string, hostedClusterID string, statusFiring bool) (*NotificationRecordItem, error) {
+	return fnr.UpdateNotificationRecordItemWithClock(clock.RealClock{}, notificationName, hostedClusterID, statusFiring)
+}
+
+// UpdateNotificationRecordItemWithClock is UpdateNotificationRecordItem with the time of the send told by the given clock
+func (fnr *ManagedFleetNotificationRecord) UpdateNotificationRecordItemWithClock(clk clock.PassiveClock, notificationName string, hostedClusterID string,
+	statusFiring bool) (*NotificationRecordItem, error) {
 	for i, nfr := range fnr.Status.NotificationRecordByName {
 		if nfr.NotificationName != notificationName {
 			continue
 		}
 		for j, nfi := range nfr.NotificationRecordItems {
 			if nfi.HostedClusterID == hostedClusterID {
+				now := clk.Now()
+				ri := &fnr.Status.NotificationRecordByName[i].NotificationRecordItems[j]
 				if statusFiring {
-					fnr.Status.NotificationRecordByName[i].NotificationRecordItems[j].FiringNotificationSentCount += 1
+					ri.FiringNotificationSentCount += 1
+					ri.RecentSendTimes = fnr.Status.NotificationRecordByName[i].recordSend(ri, now)
 				} else {
-					fnr.Status.NotificationRecordByName[i].NotificationRecordItems[j].ResolvedNotificationSentCount += 1
+					ri.ResolvedNotificationSentCount += 1
 				}
 
-				fnr.Status.NotificationRecordByName[i].NotificationRecordItems[j].LastTransitionTime = &metav1.Time{Time: time.Now()}
-				return &fnr.Status.NotificationRecordByName[i].NotificationRecordItems[j], nil
+				ri.LastTransitionTime = &metav1.Time{Time: now}
+				return ri, nil
 			}
 		}
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock/testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -18,10 +19,14 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 	)
 
 	var (
-		testMNFR *v1alpha1.ManagedFleetNotificationRecord
+		testMNFR  *v1alpha1.ManagedFleetNotificationRecord
+		fakeClock *testing.FakeClock
+		now       time.Time
 	)
 
 	BeforeEach(func() {
+		now = time.Date(2026, time.October, 14, 12, 30, 0, 0, time.UTC)
+		fakeClock = testing.NewFakeClock(now)
 		testMNFR = &v1alpha1.ManagedFleetNotificationRecord{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "test-mc-id",
@@ -44,7 +49,7 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 								HostedClusterID:               "test-hc-1-2",
 								FiringNotificationSentCount:   1,
 								ResolvedNotificationSentCount: 1,
-								LastTransitionTime:            &metav1.Time{Time: time.Now().Add(time.Duration(-5) * time.Hour)},
+								LastTransitionTime:            &metav1.Time{Time: now.Add(time.Duration(-5) * time.Hour)},
 							},
 							{
 								HostedClusterID:               "test-hc-1-3",
@@ -70,27 +75,93 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 		}
 	})
 
+	Context("When getting the notification records", func() {
+		It("returns notification records that write back into the record", func() {
+			rn, err := testMNFR.GetNotificationRecordByName(testManagementCluster, "test-notification-2")
+			Expect(err).NotTo(HaveOccurred())
+			rn.ResendWait = 48
+			Expect(testMNFR.Status.NotificationRecordByName[1].ResendWait).To(Equal(int32(48)))
+		})
+		It("returns record items that write back into the record", func() {
+			ri, err := testMNFR.GetNotificationRecordItem(testManagementCluster, testNotificationName, "test-hc-1-3")
+			Expect(err).NotTo(HaveOccurred())
+			ri.FiringNotificationSentCount = 5
+			Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[2].FiringNotificationSentCount).To(Equal(5))
+		})
+	})
+
 	Context("When updating a notification record item", func() {
 		Context("When the notification record item already exists", func() {
 			It("will update it correctly", func() {
 				firing := true
 				nr := testMNFR.Status.NotificationRecordByName[0]
 				nri := testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1]
-				nri2, err := testMNFR.UpdateNotificationRecordItem(nr.NotificationName, nri.HostedClusterID, firing)
+				nri2, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, nr.NotificationName, nri.HostedClusterID, firing)
 				Expect(err).To(BeNil())
 				Expect(nri2.FiringNotificationSentCount).To(Equal(2))
 				Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1].FiringNotificationSentCount).To(Equal(2))
 
-				nri2, err = testMNFR.UpdateNotificationRecordItem(nr.NotificationName, nri.HostedClusterID, !firing)
+				nri2, err = testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, nr.NotificationName, nri.HostedClusterID, !firing)
 				Expect(err).To(BeNil())
 				Expect(nri2.ResolvedNotificationSentCount).To(Equal(2))
 				Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1].ResolvedNotificationSentCount).To(Equal(2))
 			})
 		})
+		Context("When the notification limits the sends per window", func() {
+			BeforeEach(func() {
+				maxSends, window := int32(2), int32(24)
+				testMNFR.Status.NotificationRecordByName[0].MaxSendsPerWindow = &maxSends
+				testMNFR.Status.NotificationRecordByName[0].Window = &window
+			})
+			It("records the firing sends within the window", func() {
+				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1].RecentSendTimes = []metav1.Time{
+					{Time: now.Add(-25 * time.Hour)},
+					{Time: now.Add(-5 * time.Hour)},
+				}
+				nri, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, testNotificationName, "test-hc-1-2", true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nri.RecentSendTimes).To(HaveLen(2))
+				Expect(nri.RecentSendTimes[0].Time).To(Equal(now.Add(-5 * time.Hour)))
+				Expect(nri.RecentSendTimes[1]).To(Equal(*nri.LastTransitionTime))
+				Expect(nri.LastTransitionTime.Time).To(Equal(now))
+			})
+			It("drops a send made exactly one window ago", func() {
+				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1].RecentSendTimes = []metav1.Time{
+					{Time: now.Add(-24 * time.Hour)},
+					{Time: now.Add(-24*time.Hour + time.Nanosecond)},
+				}
+				nri, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, testNotificationName, "test-hc-1-2", true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nri.RecentSendTimes).To(Equal([]metav1.Time{
+					{Time: now.Add(-24*time.Hour + time.Nanosecond)},
+					{Time: now},
+				}))
+			})
+			It("keeps only as many sends as the limit", func() {
+				for i := 0; i < 3; i++ {
+					_, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, testNotificationName, "test-hc-1-1", true)
+					Expect(err).NotTo(HaveOccurred())
+					fakeClock.Step(time.Hour)
+				}
+				Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[0].RecentSendTimes).To(HaveLen(2))
+			})
+			It("does not record the resolved sends", func() {
+				nri, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, testNotificationName, "test-hc-1-1", false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nri.RecentSendTimes).To(BeEmpty())
+			})
+		})
+		Context("When the notification does not limit the sends per window", func() {
+			It("does not record the sends", func() {
+				nri, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, testNotificationName, "test-hc-1-1", true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nri.RecentSendTimes).To(BeNil())
+			})
+		})
 		Context("When the notification does not exist", func() {
 			It("will return an error", func() {
 				firing := false
-				_, err := testMNFR.UpdateNotificationRecordItem("nope", "nope", firing)
+				_, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, "nope", "nope", firing)
 				Expect(err).NotTo(BeNil())
 			})
 		})
@@ -98,7 +169,7 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 			It("will return an error", func() {
 				firing := false
 				nr := testMNFR.Status.NotificationRecordByName[0]
-				_, err := testMNFR.UpdateNotificationRecordItem(nr.NotificationName, "nope", firing)
+				_, err := testMNFR.UpdateNotificationRecordItemWithClock(fakeClock, nr.NotificationName, "nope", firing)
 				Expect(err).NotTo(BeNil())
 			})
 		})
@@ -112,6 +183,9 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(len(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems)).To(Equal(4))
 				Expect(nfi.HostedClusterID).To(Equal("test-hc-1-4"))
+
+				nfi.FiringNotificationSentCount = 1
+				Expect(testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[3].FiringNotificationSentCount).To(Equal(1))
 			})
 		})
 		Context("If the notification record item already exists", func() {
@@ -166,7 +240,7 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 	Context("When checking if a firing notification can be sent", func() {
 		When("there is no defined notification", func() {
 			It("will raise an error", func() {
-				cansend, err := testMNFR.FiringCanBeSent("test-mc-id-1", testNotificationName, "test-hc-1-1")
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, "test-mc-id-1", testNotificationName, "test-hc-1-1")
 				Expect(cansend).To(BeFalse())
 				Expect(err).To(HaveOccurred())
 			})
@@ -177,7 +251,7 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems = []v1alpha1.NotificationRecordItem{}
 			})
 			It("will send", func() {
-				cansend, err := testMNFR.FiringCanBeSent(testManagementCluster, testNotificationName, "test-hc-12")
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-12")
 				Expect(cansend).To(BeTrue())
 				Expect(err).To(BeNil())
 			})
@@ -186,25 +260,79 @@ var _ = Describe("OCMAgent Controller MFNR Type", func() {
 		When("the current time is within the dont-resend window", func() {
 			BeforeEach(func() {
 				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[0] = v1alpha1.NotificationRecordItem{
-					LastTransitionTime: &metav1.Time{Time: time.Now().Add(time.Duration(-5) * time.Minute)},
+					LastTransitionTime: &metav1.Time{Time: now.Add(time.Duration(-5) * time.Minute)},
 					HostedClusterID:    "test-hc-13",
 				}
 			})
 			It("will not resend", func() {
-				cansend, err := testMNFR.FiringCanBeSent(testManagementCluster, testNotificationName, "test-hc-13")
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-13")
+				Expect(cansend).To(BeFalse())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		When("the hosted cluster reached the sends per window", func() {
+			BeforeEach(func() {
+				maxSends, window := int32(2), int32(24)
+				testMNFR.Status.NotificationRecordByName[0].MaxSendsPerWindow = &maxSends
+				testMNFR.Status.NotificationRecordByName[0].Window = &window
+				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1].RecentSendTimes = []metav1.Time{
+					{Time: now.Add(-10 * time.Hour)},
+					{Time: now.Add(-5 * time.Hour)},
+				}
+			})
+			It("will not resend even though the resend wait elapsed", func() {
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-1-2")
+				Expect(cansend).To(BeFalse())
+				Expect(err).To(BeNil())
+			})
+			It("will resend once the oldest send left the window", func() {
+				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[1].RecentSendTimes[0] = metav1.Time{Time: now.Add(-25 * time.Hour)}
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-1-2")
+				Expect(cansend).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+			It("will resend exactly when the oldest send leaves the window", func() {
+				fakeClock.SetTime(now.Add(14 * time.Hour))
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-1-2")
+				Expect(cansend).To(BeTrue())
+				Expect(err).To(BeNil())
+
+				fakeClock.SetTime(now.Add(14*time.Hour - time.Nanosecond))
+				cansend, err = testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-1-2")
 				Expect(cansend).To(BeFalse())
 				Expect(err).To(BeNil())
 			})
 		})
 
+		When("the resend wait has just elapsed", func() {
+			BeforeEach(func() {
+				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[0] = v1alpha1.NotificationRecordItem{
+					LastTransitionTime: &metav1.Time{Time: now.Add(-time.Hour)},
+					HostedClusterID:    "test-hc-15",
+				}
+			})
+			It("will not resend exactly at the end of the dont-resend window", func() {
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-15")
+				Expect(cansend).To(BeFalse())
+				Expect(err).To(BeNil())
+			})
+			It("will resend just after the end of the dont-resend window", func() {
+				fakeClock.Step(time.Nanosecond)
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-15")
+				Expect(cansend).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
 		When("the current time is outside the dont-resend window", func() {
 			BeforeEach(func() {
 				testMNFR.Status.NotificationRecordByName[0].NotificationRecordItems[2] = v1alpha1.NotificationRecordItem{
-					LastTransitionTime: &metav1.Time{Time: time.Now().Add(time.Duration(-5) * time.Hour)},
+					LastTransitionTime: &metav1.Time{Time: now.Add(time.Duration(-5) * time.Hour)},
 				}
 			})
 			It("will resend notification", func() {
-				cansend, err := testMNFR.FiringCanBeSent(testManagementCluster, testNotificationName, "test-hc-14")
+				cansend, err := testMNFR.FiringCanBeSentWithClock(fakeClock, testManagementCluster, testNotificationName, "test-hc-14")
 				Expect(cansend).To(BeTrue())
 				Expect(err).To(BeNil())
 			})
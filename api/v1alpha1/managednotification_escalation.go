@@ -0,0 +1,115 @@
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// EscalationPolicy raises the severity of a notification that keeps firing, and optionally changes
+// what it says, so that customers notice an alert that was not acted upon
+type EscalationPolicy struct {
+	// The notification is escalated once this many service logs have been sent for it
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AfterSends *int32 `json:"afterSends,omitempty"`
+
+	// Measured in hours. The notification is escalated once its alert has been firing continuously for this long
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AfterFiringHours *int32 `json:"afterFiringHours,omitempty"`
+
+	// The severity of the escalated notification, which must be higher than the severity of the notification
+	// +kubebuilder:validation:Enum={"Debug","Info","Warning","Major","Critical","Error","Fatal"}
+	Severity NotificationSeverity `json:"severity"`
+
+	// The summary line of the escalated notification. Defaults to the summary of the notification.
+	// +optional
+	Summary string `json:"summary,omitempty"`
+
+	// The body text of the escalated notification when the alert is active. Defaults to the active body of the notification.
+	// A Go template when the notification is templated.
+	// +optional
+	ActiveDesc string `json:"activeBody,omitempty"`
+}
+
+// Applies reports whether the escalation applies to a notification with the given record at the given time.
+// The alert is firing continuously since the last transition of its AlertFiring condition.
+func (e *EscalationPolicy) Applies(record *NotificationRecord, now time.Time) bool {
+	if record == nil {
+		return false
+	}
+
+	if e.AfterSends != nil && record.ServiceLogSentCount >= *e.AfterSends {
+		return true
+	}
+
+	if e.AfterFiringHours != nil {
+		firing := record.Conditions.GetCondition(ConditionAlertFiring)
+		if firing == nil || firing.Status != corev1.ConditionTrue || firing.LastTransitionTime == nil {
+			return false
+		}
+		escalatesAt := firing.LastTransitionTime.Add(time.Duration(*e.AfterFiringHours) * time.Hour)
+		return !now.Before(escalatesAt)
+	}
+
+	return false
+}
+
+// EffectiveNotification returns the notification to send for the given name at the given time: a copy of
+// the notification with the severity, summary and active body of its escalation policy when the policy
+// applies to the notification record, and a copy of the notification itself otherwise
+func (m *ManagedNotification) EffectiveNotification(n string, now time.Time) (*Notification, error) {
+	t, err := m.GetNotificationForName(n)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := t.DeepCopy()
+	e := t.Escalation
+	if e == nil || !e.Applies(m.Status.NotificationRecords.GetNotificationRecord(n), now) {
+		return effective, nil
+	}
+
+	effective.Severity = e.Severity
+	if e.Summary != "" {
+		effective.Summary = e.Summary
+	}
+	if e.ActiveDesc != "" {
+		effective.ActiveDesc = e.ActiveDesc
+	}
+	return effective, nil
+}
+
+// validate checks the escalation policy of a notification of the given severity for problems the CRD
+// schema cannot express
+func (e *EscalationPolicy) validate(path *field.Path, severity NotificationSeverity, templated bool) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if e.AfterSends == nil && e.AfterFiringHours == nil {
+		allErrs = append(allErrs, field.Required(path, "an escalation policy needs afterSends or afterFiringHours"))
+	}
+	if e.AfterSends != nil && *e.AfterSends < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("afterSends"), *e.AfterSends, "afterSends must be at least 1"))
+	}
+	if e.AfterFiringHours != nil && *e.AfterFiringHours < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("afterFiringHours"), *e.AfterFiringHours, "afterFiringHours must be at least 1"))
+	}
+
+	if templated {
+		if err := ValidateNotificationTemplate(e.ActiveDesc); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("activeBody"), e.ActiveDesc, err.Error()))
+		}
+	}
+
+	rank, ok := severityRank[e.Severity]
+	if !ok {
+		allErrs = append(allErrs, field.NotSupported(path.Child("severity"), e.Severity, severityNames()))
+	} else if base, ok := severityRank[severity]; ok && rank <= base {
+		allErrs = append(allErrs, field.Invalid(path.Child("severity"), e.Severity,
+			"the escalated severity must be higher than the severity of the notification"))
+	}
+
+	return allErrs
+}
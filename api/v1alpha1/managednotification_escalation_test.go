@@ -0,0 +1,134 @@
+package v1alpha1_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+var _ = Describe("ManagedNotification Escalation", func() {
+
+	const testNotificationName = "test-notification"
+
+	var (
+		mn          *v1alpha1.ManagedNotification
+		fakeClock   *testing.FakeClock
+		firingSince time.Time
+	)
+
+	BeforeEach(func() {
+		firingSince = time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)
+		fakeClock = testing.NewFakeClock(firingSince)
+		mn = &v1alpha1.ManagedNotification{
+			Spec: v1alpha1.ManagedNotificationSpec{
+				Notifications: []v1alpha1.Notification{
+					{
+						Name:       testNotificationName,
+						Summary:    "test summary",
+						ActiveDesc: "test active",
+						Severity:   v1alpha1.SeverityWarning,
+						ResendWait: 1,
+						Escalation: &v1alpha1.EscalationPolicy{
+							AfterSends:       ptr.To(int32(3)),
+							AfterFiringHours: ptr.To(int32(6)),
+							Severity:         v1alpha1.SeverityMajor,
+							Summary:          "test escalated summary",
+						},
+					},
+				},
+			},
+			Status: v1alpha1.ManagedNotificationStatus{
+				NotificationRecords: v1alpha1.NotificationRecords{
+					{
+						Name:                testNotificationName,
+						ServiceLogSentCount: 1,
+						Conditions: []v1alpha1.NotificationCondition{
+							{
+								Type:               v1alpha1.ConditionAlertFiring,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: &metav1.Time{Time: firingSince},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	Context("Computing the effective notification", func() {
+		It("sends the notification unchanged until the escalation applies", func() {
+			fakeClock.Step(6*time.Hour - time.Second)
+			n, err := mn.EffectiveNotification(testNotificationName, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.Severity).To(Equal(v1alpha1.SeverityWarning))
+			Expect(n.Summary).To(Equal("test summary"))
+		})
+		It("escalates once the alert has been firing for the escalation hours", func() {
+			fakeClock.Step(6 * time.Hour)
+			n, err := mn.EffectiveNotification(testNotificationName, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.Severity).To(Equal(v1alpha1.SeverityMajor))
+			Expect(n.Summary).To(Equal("test escalated summary"))
+			Expect(n.ActiveDesc).To(Equal("test active"))
+			Expect(mn.Spec.Notifications[0].Severity).To(Equal(v1alpha1.SeverityWarning))
+		})
+		It("escalates once the escalation sends have been sent", func() {
+			mn.Status.NotificationRecords[0].ServiceLogSentCount = 3
+			n, err := mn.EffectiveNotification(testNotificationName, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.Severity).To(Equal(v1alpha1.SeverityMajor))
+		})
+		It("does not escalate a resolved alert on the firing time", func() {
+			mn.Status.NotificationRecords[0].Conditions[0].Status = corev1.ConditionFalse
+			fakeClock.Step(24 * time.Hour)
+			n, err := mn.EffectiveNotification(testNotificationName, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.Severity).To(Equal(v1alpha1.SeverityWarning))
+		})
+		It("does not escalate a notification without a record", func() {
+			mn.Status.NotificationRecords = nil
+			fakeClock.Step(24 * time.Hour)
+			n, err := mn.EffectiveNotification(testNotificationName, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n.Severity).To(Equal(v1alpha1.SeverityWarning))
+		})
+		It("errors when the notification does not exist", func() {
+			_, err := mn.EffectiveNotification("nope", fakeClock.Now())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Validating the escalation policy", func() {
+		It("accepts a valid escalation policy", func() {
+			Expect(mn.Validate()).To(BeEmpty())
+		})
+		It("requires a threshold", func() {
+			mn.Spec.Notifications[0].Escalation.AfterSends = nil
+			mn.Spec.Notifications[0].Escalation.AfterFiringHours = nil
+			errs := mn.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.notifications[0].escalation"))
+		})
+		It("rejects thresholds below 1", func() {
+			mn.Spec.Notifications[0].Escalation.AfterSends = ptr.To(int32(0))
+			mn.Spec.Notifications[0].Escalation.AfterFiringHours = ptr.To(int32(-1))
+			errs := mn.Validate()
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Field).To(Equal("spec.notifications[0].escalation.afterSends"))
+			Expect(errs[1].Field).To(Equal("spec.notifications[0].escalation.afterFiringHours"))
+		})
+		It("requires the escalated severity to be higher", func() {
+			mn.Spec.Notifications[0].Escalation.Severity = v1alpha1.SeverityInfo
+			errs := mn.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.notifications[0].escalation.severity"))
+		})
+	})
+})
@@ -0,0 +1,195 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DeliverySchedule restricts the times a notification is delivered, so that notifications that are not
+// urgent do not reach customers outside their working hours
+type DeliverySchedule struct {
+	// The time windows in which notifications are delivered. A notification due outside of them is
+	// deferred until the next window opens.
+	// +kubebuilder:validation:MinItems=1
+	Windows []DeliveryWindow `json:"windows"`
+
+	// The IANA time zone the windows are expressed in, for example Europe/Prague. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Notifications of this severity or higher are delivered outside the windows, in the order
+	// Debug, Info, Warning, Major, Error, Critical, Fatal. Defaults to Major.
+	// +kubebuilder:validation:Enum={"Debug","Info","Warning","Major","Critical","Error","Fatal"}
+	// +optional
+	BypassSeverity NotificationSeverity `json:"bypassSeverity,omitempty"`
+}
+
+// DeliveryWindow is a daily time window in which notifications are delivered
+type DeliveryWindow struct {
+	// The days of the week the window opens on. Defaults to every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// The time the window opens, as HH:MM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// The time the window closes, as HH:MM. A window closing at or before the time it opens
+	// spans midnight and closes the next day.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+}
+
+// +kubebuilder:validation:Enum={"Monday","Tuesday","Wednesday","Thursday","Friday","Saturday","Sunday"}
+type Weekday string
+
+// deliveryTimeLayout is the layout of the start and end times of a delivery window
+const deliveryTimeLayout = "15:04"
+
+// severityRank orders the severities for the bypass severity of a delivery schedule and the escalation
+// severity. The severities mix the impact levels of service logs (Info, Warning, Major, Critical) with the
+// log levels (Debug, Info, Warning, Error, Fatal). As with log levels, Critical is above Error and only
+// Fatal is above Critical, while Major is an impact short of an error.
+var severityRank = map[NotificationSeverity]int{
+	SeverityDebug:    0,
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityMajor:    3,
+	SeverityError:    4,
+	SeverityCritical: 5,
+	SeverityFatal:    6,
+}
+
+// weekdays maps the days of a delivery window to the days of the week
+var weekdays = map[Weekday]time.Weekday{
+	"Sunday":    time.Sunday,
+	"Monday":    time.Monday,
+	"Tuesday":   time.Tuesday,
+	"Wednesday": time.Wednesday,
+	"Thursday":  time.Thursday,
+	"Friday":    time.Friday,
+	"Saturday":  time.Saturday,
+}
+
+// Bypasses reports whether a notification of the given severity is delivered outside the windows
+func (s *DeliverySchedule) Bypasses(severity NotificationSeverity) bool {
+	bypass := s.BypassSeverity
+	if bypass == "" {
+		bypass = SeverityMajor
+	}
+	rank, ok := severityRank[severity]
+	return ok && rank >= severityRank[bypass]
+}
+
+// NextDelivery returns the time a notification of the given severity due at now can be delivered.
+// The zero time is returned when it can be delivered now.
+func (s *DeliverySchedule) NextDelivery(now time.Time, severity NotificationSeverity) (time.Time, error) {
+	if s.Bypasses(severity) {
+		return time.Time{}, nil
+	}
+
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid delivery schedule time zone %q: %w", s.TimeZone, err)
+	}
+	local := now.In(loc)
+
+	var next time.Time
+	// A window opened the day before may still be open, and every window opens within a week
+	for day := -1; day <= 7; day++ {
+		date := local.AddDate(0, 0, day)
+		for _, w := range s.Windows {
+			opens, closes, ok, err := w.on(date, loc)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if !ok {
+				continue
+			}
+			if !local.Before(opens) && local.Before(closes) {
+				return time.Time{}, nil
+			}
+			if opens.After(local) && (next.IsZero() || opens.Before(next)) {
+				next = opens
+			}
+		}
+	}
+	return next, nil
+}
+
+// on returns the times the window opens and closes when it opens on the day of the given date
+func (w *DeliveryWindow) on(date time.Time, loc *time.Location) (time.Time, time.Time, bool, error) {
+	if len(w.Days) > 0 {
+		applies := false
+		for _, d := range w.Days {
+			if weekdays[d] == date.Weekday() {
+				applies = true
+				break
+			}
+		}
+		if !applies {
+			return time.Time{}, time.Time{}, false, nil
+		}
+	}
+
+	start, err := time.Parse(deliveryTimeLayout, w.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("invalid delivery window start %q: %w", w.Start, err)
+	}
+	end, err := time.Parse(deliveryTimeLayout, w.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("invalid delivery window end %q: %w", w.End, err)
+	}
+
+	opens := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+	closes := time.Date(date.Year(), date.Month(), date.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+	if !closes.After(opens) {
+		closes = closes.AddDate(0, 0, 1)
+	}
+	return opens, closes, true, nil
+}
+
+// validate checks the schedule for problems the CRD schema cannot express
+func (s *DeliverySchedule) validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(s.Windows) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("windows"), "a delivery schedule needs at least one window"))
+	}
+
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), s.TimeZone, "unknown time zone"))
+	}
+
+	if _, ok := severityRank[s.BypassSeverity]; s.BypassSeverity != "" && !ok {
+		allErrs = append(allErrs, field.NotSupported(path.Child("bypassSeverity"), s.BypassSeverity, severityNames()))
+	}
+
+	for i, w := range s.Windows {
+		windowPath := path.Child("windows").Index(i)
+		if _, err := time.Parse(deliveryTimeLayout, w.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("start"), w.Start, "must be a time as HH:MM"))
+		}
+		if _, err := time.Parse(deliveryTimeLayout, w.End); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("end"), w.End, "must be a time as HH:MM"))
+		}
+		for j, d := range w.Days {
+			if _, ok := weekdays[d]; !ok {
+				allErrs = append(allErrs, field.Invalid(windowPath.Child("days").Index(j), d, "must be a day of the week"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// severityNames returns the severities in increasing order
+func severityNames() []string {
+	names := make([]string, len(severityRank))
+	for severity, rank := range severityRank {
+		names[rank] = string(severity)
+	}
+	return names
+}
@@ -0,0 +1,174 @@
+package v1alpha1_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+var _ = Describe("ManagedNotification Delivery Schedule", func() {
+
+	var (
+		schedule *v1alpha1.DeliverySchedule
+		// A Wednesday
+		wednesday = time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+	)
+
+	at := func(day time.Time, hour, minute int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+	}
+
+	BeforeEach(func() {
+		schedule = &v1alpha1.DeliverySchedule{
+			Windows: []v1alpha1.DeliveryWindow{
+				{Start: "09:00", End: "17:00"},
+			},
+		}
+	})
+
+	Context("Computing the next delivery", func() {
+		It("delivers a notification inside a window now", func() {
+			next, err := schedule.NextDelivery(at(wednesday, 12, 30), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeZero())
+		})
+		It("defers a notification until the window opens", func() {
+			next, err := schedule.NextDelivery(at(wednesday, 7, 15), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(at(wednesday, 9, 0)))
+		})
+		It("defers a notification due after the window closes to the next day", func() {
+			next, err := schedule.NextDelivery(at(wednesday, 17, 0), v1alpha1.SeverityWarning)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(at(wednesday.AddDate(0, 0, 1), 9, 0)))
+		})
+		It("delivers a notification of the bypass severity or higher now", func() {
+			next, err := schedule.NextDelivery(at(wednesday, 3, 0), v1alpha1.SeverityMajor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeZero())
+
+			schedule.BypassSeverity = v1alpha1.SeverityFatal
+			next, err = schedule.NextDelivery(at(wednesday, 3, 0), v1alpha1.SeverityCritical)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(at(wednesday, 9, 0)))
+		})
+		It("orders the severities from Debug to Fatal with Critical above Error", func() {
+			severities := []v1alpha1.NotificationSeverity{
+				v1alpha1.SeverityDebug,
+				v1alpha1.SeverityInfo,
+				v1alpha1.SeverityWarning,
+				v1alpha1.SeverityMajor,
+				v1alpha1.SeverityError,
+				v1alpha1.SeverityCritical,
+				v1alpha1.SeverityFatal,
+			}
+			for i, bypass := range severities {
+				schedule.BypassSeverity = bypass
+				for j, severity := range severities {
+					Expect(schedule.Bypasses(severity)).To(Equal(j >= i), "%s with bypass severity %s", severity, bypass)
+				}
+			}
+		})
+		It("delivers inside a window spanning midnight", func() {
+			schedule.Windows = []v1alpha1.DeliveryWindow{{Start: "22:00", End: "06:00"}}
+			next, err := schedule.NextDelivery(at(wednesday, 2, 0), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeZero())
+			next, err = schedule.NextDelivery(at(wednesday, 6, 0), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(at(wednesday, 22, 0)))
+		})
+		It("only opens the windows on their days", func() {
+			schedule.Windows[0].Days = []v1alpha1.Weekday{"Monday", "Friday"}
+			next, err := schedule.NextDelivery(at(wednesday, 12, 0), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(at(wednesday.AddDate(0, 0, 2), 9, 0)))
+
+			next, err = schedule.NextDelivery(at(wednesday.AddDate(0, 0, 2), 18, 0), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(at(wednesday.AddDate(0, 0, 5), 9, 0)))
+		})
+		It("picks the earliest of several windows", func() {
+			schedule.Windows = append(schedule.Windows, v1alpha1.DeliveryWindow{Start: "18:00", End: "19:00"})
+			next, err := schedule.NextDelivery(at(wednesday, 17, 30), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(Equal(at(wednesday, 18, 0)))
+		})
+		It("expresses the windows in the time zone of the schedule", func() {
+			schedule.TimeZone = "Asia/Tokyo"
+			// 01:00 UTC is 10:00 in Tokyo
+			next, err := schedule.NextDelivery(at(wednesday, 1, 0), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeZero())
+			// 08:00 UTC is 17:00 in Tokyo, the window opens again at 00:00 UTC the next day
+			next, err = schedule.NextDelivery(at(wednesday, 8, 0), v1alpha1.SeverityInfo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next).To(BeTemporally("==", at(wednesday.AddDate(0, 0, 1), 0, 0)))
+		})
+		It("errors on an unknown time zone", func() {
+			schedule.TimeZone = "Nowhere/Special"
+			_, err := schedule.NextDelivery(at(wednesday, 12, 0), v1alpha1.SeverityInfo)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Validating the schedule", func() {
+		It("accepts a valid notification schedule", func() {
+			mn := &v1alpha1.ManagedNotification{
+				Spec: v1alpha1.ManagedNotificationSpec{
+					Notifications: []v1alpha1.Notification{
+						{
+							Name:       "test-notification",
+							Summary:    "test",
+							ActiveDesc: "test active",
+							Severity:   v1alpha1.SeverityInfo,
+							ResendWait: 1,
+							Schedule:   schedule,
+						},
+					},
+				},
+			}
+			Expect(mn.Validate()).To(BeEmpty())
+		})
+		It("reports the problems of an invalid schedule", func() {
+			schedule.TimeZone = "Nowhere/Special"
+			schedule.BypassSeverity = "Urgent"
+			schedule.Windows = append(schedule.Windows, v1alpha1.DeliveryWindow{
+				Days:  []v1alpha1.Weekday{"Someday"},
+				Start: "25:00",
+				End:   "9am",
+			})
+			mn := &v1alpha1.ManagedNotification{
+				Spec: v1alpha1.ManagedNotificationSpec{
+					Notifications: []v1alpha1.Notification{
+						{
+							Name:       "test-notification",
+							Summary:    "test",
+							ActiveDesc: "test active",
+							Severity:   v1alpha1.SeverityInfo,
+							ResendWait: 1,
+							Schedule:   schedule,
+						},
+					},
+				},
+			}
+			errs := mn.Validate()
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			Expect(fields).To(ConsistOf(
+				"spec.notifications[0].schedule.timeZone",
+				"spec.notifications[0].schedule.bypassSeverity",
+				"spec.notifications[0].schedule.windows[1].start",
+				"spec.notifications[0].schedule.windows[1].end",
+				"spec.notifications[0].schedule.windows[1].days[0]",
+			))
+			Expect(errs[1].Type).To(Equal(field.ErrorTypeNotSupported))
+		})
+	})
+})
@@ -18,10 +18,14 @@ package v1alpha1
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/clock"
 )
 
 type NotificationSeverity string
@@ -47,12 +51,17 @@ type Notification struct {
 	// The summary line of the Service Log notification
 	Summary string `json:"summary"`
 
-	// The body text of the Service Log notification when the alert is active
+	// The body text of the Service Log notification when the alert is active. A Go template when templated is set.
 	ActiveDesc string `json:"activeBody"`
 
-	// The body text of the Service Log notification when the alert is resolved
+	// The body text of the Service Log notification when the alert is resolved. A Go template when templated is set.
 	ResolvedDesc string `json:"resolvedBody,omitempty"`
 
+	// Whether the bodies are Go templates referring to the labels, annotations and times of the alert, such as
+	// {{ .Labels.namespace }}. The bodies are sent as written when unset, even if they contain {{.
+	// +optional
+	Templated bool `json:"templated,omitempty"`
+
 	// LogType is a categorization property that can be used to group service logs for aggregation and managing notification preferences.
 	LogType string `json:"logType,omitempty"`
 
@@ -65,23 +74,35 @@ type Notification struct {
 
 	// Measured in hours. The minimum time interval that must elapse between active Service Log notifications
 	ResendWait int32 `json:"resendWait"`
+
+	// The times the Service Log notifications are delivered. Notifications due outside of them are deferred
+	// until the next window opens, unless their severity bypasses the schedule. Delivered at any time when unset.
+	// +optional
+	Schedule *DeliverySchedule `json:"schedule,omitempty"`
+
+	// Raises the severity of the notification when its alert keeps firing. Never escalated when unset.
+	// +optional
+	Escalation *EscalationPolicy `json:"escalation,omitempty"`
 }
 
 // ManagedNotificationSpec defines the desired state of ManagedNotification
 type ManagedNotificationSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-
 	// AgentConfig refers to OCM agent config fields separated
 	Notifications []Notification `json:"notifications"`
 }
 
+// ManagedNotificationConditionReady indicates whether all notifications of a ManagedNotification are valid
+const ManagedNotificationConditionReady = "Ready"
+
 // ManagedNotificationStatus defines the observed state of ManagedNotification
 type ManagedNotificationStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-
 	NotificationRecords NotificationRecords `json:"notificationRecords,omitempty"`
+
+	// Conditions represent the latest available observations of the ManagedNotification state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type NotificationRecords []NotificationRecord
@@ -183,15 +204,148 @@ func (m *ManagedNotificationStatus) HasNotificationRecord(n string) bool {
 	return false
 }
 
-// CanBeSent returns true if a service log from the notification is allowed to be sent
+// Validate checks the notifications for problems the CRD schema cannot express and
+// returns every problem found.
+func (m *ManagedNotification) Validate() field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(m.Spec.Notifications))
+	notificationsPath := field.NewPath("spec", "notifications")
+
+	for i, n := range m.Spec.Notifications {
+		path := notificationsPath.Index(i)
+
+		if n.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("name"), "notification name must not be empty"))
+		} else if seen[n.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), n.Name))
+		}
+		seen[n.Name] = true
+
+		if strings.TrimSpace(n.Summary) == "" {
+			allErrs = append(allErrs, field.Required(path.Child("summary"), "notification summary must not be empty"))
+		}
+
+		if n.ResendWait < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("resendWait"), n.ResendWait, "resendWait must not be negative"))
+		}
+
+		// A resolved notification is only ever sent after a firing one
+		if n.ResolvedDesc != "" && strings.TrimSpace(n.ActiveDesc) == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("activeBody"), n.ActiveDesc,
+				"a resolvedBody requires an activeBody for the firing alert"))
+		}
+
+		if n.Templated {
+			if err := ValidateNotificationTemplate(n.ActiveDesc); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("activeBody"), n.ActiveDesc, err.Error()))
+			}
+			if err := ValidateNotificationTemplate(n.ResolvedDesc); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("resolvedBody"), n.ResolvedDesc, err.Error()))
+			}
+		}
+
+		for j, ref := range n.References {
+			if err := validateReference(ref); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("references").Index(j), ref, err.Error()))
+			}
+		}
+
+		if n.Schedule != nil {
+			allErrs = append(allErrs, n.Schedule.validate(path.Child("schedule"))...)
+		}
+
+		if n.Escalation != nil {
+			allErrs = append(allErrs, n.Escalation.validate(path.Child("escalation"), n.Severity, n.Templated)...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateReference checks that the reference is an absolute http(s) URL
+func validateReference(ref NotificationReferenceType) error {
+	u, err := url.ParseRequestURI(string(ref))
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q, must be http or https", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("reference must contain a host")
+	}
+	return nil
+}
+
+// PruneNotificationRecords removes the notification records which no longer have a
+// matching notification in the spec and returns the names of the removed records.
+func (m *ManagedNotification) PruneNotificationRecords() []string {
+	names := make(map[string]bool, len(m.Spec.Notifications))
+	for _, n := range m.Spec.Notifications {
+		names[n.Name] = true
+	}
+
+	var pruned []string
+	records := NotificationRecords{}
+	for _, r := range m.Status.NotificationRecords {
+		if !names[r.Name] {
+			pruned = append(pruned, r.Name)
+			continue
+		}
+		records = append(records, r)
+	}
+	if len(pruned) > 0 {
+		m.Status.NotificationRecords = records
+	}
+	return pruned
+}
+
+// CanBeSent returns true if a service log from the notification is allowed to be sent. A service log due
+// outside the delivery schedule of the notification cannot be sent yet, see CanBeSentAt for when it can.
 func (m *ManagedNotification) CanBeSent(n string, firing bool) (bool, error) {
+	return m.CanBeSentWithClock(clock.RealClock{}, n, firing)
+}
+
+// CanBeSentWithClock is CanBeSent with the current time told by the given clock
+func (m *ManagedNotification) CanBeSentWithClock(clk clock.PassiveClock, n string, firing bool) (bool, error) {
+	canBeSent, _, err := m.CanBeSentAt(n, firing, clk.Now())
+	return canBeSent, err
+}
+
+// CanBeSentAt returns true if a service log from the notification is allowed to be sent at the given time.
+// When it is allowed but due outside the delivery schedule of the notification, false is returned along
+// with the time it is deferred until, so that it can be sent then.
+func (m *ManagedNotification) CanBeSentAt(n string, firing bool, now time.Time) (bool, time.Time, error) {
 
 	// If no notification exists, one cannot be sent
 	t, err := m.GetNotificationForName(n)
 	if err != nil {
-		return false, err
+		return false, time.Time{}, err
+	}
+
+	canBeSent, err := m.canBeSentNow(t, firing, now)
+	if err != nil || !canBeSent || t.Schedule == nil {
+		return canBeSent, time.Time{}, err
+	}
+
+	// An escalated notification may bypass the schedule the notification does not
+	effective, err := m.EffectiveNotification(n, now)
+	if err != nil {
+		return false, time.Time{}, err
 	}
 
+	deferredUntil, err := t.Schedule.NextDelivery(now, effective.Severity)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	return deferredUntil.IsZero(), deferredUntil, nil
+}
+
+// canBeSentNow returns true if a service log from the notification is allowed to be sent at the
+// given time, regardless of its delivery schedule
+func (m *ManagedNotification) canBeSentNow(t *Notification, firing bool, now time.Time) (bool, error) {
+	n := t.Name
+
 	hasNotificationRecord := m.Status.HasNotificationRecord(n)
 
 	// If alert is firing
@@ -212,7 +366,6 @@ func (m *ManagedNotification) CanBeSent(n string, firing bool) (bool, error) {
 			// No service log send recorded yet, it can be sent
 			return true, nil
 		}
-		now := time.Now()
 		nextresend := sentCondition.LastTransitionTime.Add(time.Duration(t.ResendWait) * time.Hour)
 		if now.Before(nextresend) && sentCondition.Status == corev1.ConditionTrue {
 			return false, nil
@@ -7,6 +7,8 @@ import (
 	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -22,9 +24,13 @@ var _ = Describe("OCMAgent Controller", func() {
 	var (
 		testManagedNotification    *v1alpha1.ManagedNotification
 		testManagedNotificationWrb *v1alpha1.ManagedNotification
+		fakeClock                  *testing.FakeClock
+		now                        time.Time
 	)
 
 	BeforeEach(func() {
+		now = time.Date(2026, time.October, 14, 12, 30, 0, 0, time.UTC)
+		fakeClock = testing.NewFakeClock(now)
 		testManagedNotification = &v1alpha1.ManagedNotification{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "test",
@@ -51,19 +57,19 @@ var _ = Describe("OCMAgent Controller", func() {
 							{
 								Type:               v1alpha1.ConditionAlertFiring,
 								Status:             corev1.ConditionTrue,
-								LastTransitionTime: &metav1.Time{Time: time.Now()},
+								LastTransitionTime: &metav1.Time{Time: now},
 								Reason:             "Test reason",
 							},
 							{
 								Type:               v1alpha1.ConditionAlertResolved,
 								Status:             corev1.ConditionTrue,
-								LastTransitionTime: &metav1.Time{Time: time.Now()},
+								LastTransitionTime: &metav1.Time{Time: now},
 								Reason:             "Test reason",
 							},
 							{
 								Type:               v1alpha1.ConditionServiceLogSent,
 								Status:             corev1.ConditionTrue,
-								LastTransitionTime: &metav1.Time{Time: time.Now()},
+								LastTransitionTime: &metav1.Time{Time: now},
 								Reason:             "Test reason",
 							},
 						},
@@ -96,7 +102,7 @@ var _ = Describe("OCMAgent Controller", func() {
 							{
 								Type:               v1alpha1.ConditionAlertFiring,
 								Status:             corev1.ConditionTrue,
-								LastTransitionTime: &metav1.Time{Time: time.Now()},
+								LastTransitionTime: &metav1.Time{Time: now},
 								Reason:             "Test reason",
 							},
 						},
@@ -106,6 +112,43 @@ var _ = Describe("OCMAgent Controller", func() {
 		}
 	})
 
+	Context("When validating the notifications", func() {
+		It("accepts valid notifications", func() {
+			Expect(testManagedNotification.Validate()).To(BeEmpty())
+		})
+
+		It("rejects duplicate notification names", func() {
+			testManagedNotification.Spec.Notifications = append(testManagedNotification.Spec.Notifications,
+				testManagedNotification.Spec.Notifications[0])
+			errs := testManagedNotification.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.notifications[1].name"))
+		})
+
+		It("rejects bodies with invalid templates", func() {
+			testManagedNotification.Spec.Notifications[0].Templated = true
+			testManagedNotification.Spec.Notifications[0].ActiveDesc = "Namespace {{ .Labels.namespace }} on {{ .Cluster }}"
+			testManagedNotification.Spec.Notifications[0].ResolvedDesc = "Resolved {{ end }}"
+			errs := testManagedNotification.Validate()
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Field).To(Equal("spec.notifications[0].activeBody"))
+			Expect(errs[1].Field).To(Equal("spec.notifications[0].resolvedBody"))
+		})
+
+		It("accepts bodies with template actions when the notification is not templated", func() {
+			testManagedNotification.Spec.Notifications[0].ActiveDesc = "Run oc get pods -o go-template='{{ .metadata.name }}'"
+			testManagedNotification.Spec.Notifications[0].ResolvedDesc = "Resolved {{ end }}"
+			Expect(testManagedNotification.Validate()).To(BeEmpty())
+		})
+
+		It("rejects a negative resend wait", func() {
+			testManagedNotification.Spec.Notifications[0].ResendWait = -1
+			errs := testManagedNotification.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.notifications[0].resendWait"))
+		})
+	})
+
 	Context("When retrieving a notification", func() {
 		It("will raise an error if the notification is not found", func() {
 			t, err := testManagedNotification.GetNotificationForName("nonexistant")
@@ -142,7 +185,7 @@ var _ = Describe("OCMAgent Controller", func() {
 
 		When("there is no defined notification", func() {
 			It("will raise an error", func() {
-				cansend, err := testManagedNotification.CanBeSent("nonexistant", true)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, "nonexistant", true)
 				Expect(cansend).To(BeFalse())
 				Expect(err).To(HaveOccurred())
 			})
@@ -153,7 +196,7 @@ var _ = Describe("OCMAgent Controller", func() {
 				testManagedNotification.Status.NotificationRecords = []v1alpha1.NotificationRecord{}
 			})
 			It("will send", func() {
-				cansend, err := testManagedNotification.CanBeSent(testNotificationName, true)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, true)
 				Expect(cansend).To(BeTrue())
 				Expect(err).To(BeNil())
 			})
@@ -164,12 +207,12 @@ var _ = Describe("OCMAgent Controller", func() {
 				testManagedNotification.Status.NotificationRecords[0].Conditions[2] = v1alpha1.NotificationCondition{
 					Type:               v1alpha1.ConditionServiceLogSent,
 					Status:             corev1.ConditionTrue,
-					LastTransitionTime: &metav1.Time{Time: time.Now().Add(time.Duration(-5) * time.Minute)},
+					LastTransitionTime: &metav1.Time{Time: now.Add(time.Duration(-5) * time.Minute)},
 					Reason:             "test",
 				}
 			})
 			It("will not resend", func() {
-				cansend, err := testManagedNotification.CanBeSent(testNotificationName, true)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, true)
 				Expect(cansend).To(BeFalse())
 				Expect(err).To(BeNil())
 			})
@@ -180,41 +223,131 @@ var _ = Describe("OCMAgent Controller", func() {
 				testManagedNotification.Status.NotificationRecords[0].Conditions[2] = v1alpha1.NotificationCondition{
 					Type:               v1alpha1.ConditionServiceLogSent,
 					Status:             corev1.ConditionFalse,
-					LastTransitionTime: &metav1.Time{Time: time.Now().Add(time.Duration(-5) * time.Minute)},
+					LastTransitionTime: &metav1.Time{Time: now.Add(time.Duration(-5) * time.Minute)},
 					Reason:             "test",
 				}
 			})
 			It("will resend", func() {
-				cansend, err := testManagedNotification.CanBeSent(testNotificationName, true)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, true)
 				Expect(cansend).To(BeTrue())
 				Expect(err).To(BeNil())
 			})
 		})
 
+		When("the resend wait has just elapsed", func() {
+			BeforeEach(func() {
+				testManagedNotification.Status.NotificationRecords[0].Conditions[2] = v1alpha1.NotificationCondition{
+					Type:               v1alpha1.ConditionServiceLogSent,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: &metav1.Time{Time: now.Add(-time.Hour)},
+					Reason:             "test",
+				}
+			})
+			It("will resend exactly at the end of the dont-resend window", func() {
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, true)
+				Expect(cansend).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+			It("will not resend just before the end of the dont-resend window", func() {
+				fakeClock.SetTime(now.Add(-time.Nanosecond))
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, true)
+				Expect(cansend).To(BeFalse())
+				Expect(err).To(BeNil())
+			})
+		})
+
 		When("the current time is outside the dont-resend window", func() {
 			BeforeEach(func() {
 				testManagedNotification.Status.NotificationRecords[0].Conditions[2] = v1alpha1.NotificationCondition{
 					Type:               v1alpha1.ConditionServiceLogSent,
 					Status:             corev1.ConditionTrue,
-					LastTransitionTime: &metav1.Time{Time: time.Now().Add(time.Duration(-5) * time.Hour)},
+					LastTransitionTime: &metav1.Time{Time: now.Add(time.Duration(-5) * time.Hour)},
 					Reason:             "test",
 				}
 			})
 			It("will resend", func() {
-				cansend, err := testManagedNotification.CanBeSent(testNotificationName, true)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, true)
 				Expect(cansend).To(BeTrue())
 				Expect(err).To(BeNil())
 			})
 		})
 	})
 
+	Context("When checking if a notification with a delivery schedule can be sent", func() {
+		BeforeEach(func() {
+			testManagedNotification.Status.NotificationRecords = []v1alpha1.NotificationRecord{}
+			// The window opens an hour after now, so the notification is due outside it
+			testManagedNotification.Spec.Notifications[0].Schedule = &v1alpha1.DeliverySchedule{
+				Windows: []v1alpha1.DeliveryWindow{{Start: "13:30", End: "14:30"}},
+			}
+		})
+
+		It("defers the notification until the window opens", func() {
+			cansend, deferredUntil, err := testManagedNotification.CanBeSentAt(testNotificationName, true, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cansend).To(BeFalse())
+			Expect(deferredUntil).To(Equal(now.Add(time.Hour)))
+		})
+
+		It("sends the notification exactly when the window opens", func() {
+			fakeClock.SetTime(now.Add(time.Hour))
+			cansend, deferredUntil, err := testManagedNotification.CanBeSentAt(testNotificationName, true, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cansend).To(BeTrue())
+			Expect(deferredUntil).To(BeZero())
+		})
+
+		It("defers the notification to the next day exactly when the window closes", func() {
+			fakeClock.SetTime(now.Add(2 * time.Hour))
+			cansend, deferredUntil, err := testManagedNotification.CanBeSentAt(testNotificationName, true, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cansend).To(BeFalse())
+			Expect(deferredUntil).To(Equal(now.Add(25 * time.Hour)))
+		})
+
+		It("sends a notification whose severity bypasses the schedule", func() {
+			testManagedNotification.Spec.Notifications[0].Severity = v1alpha1.SeverityCritical
+			cansend, deferredUntil, err := testManagedNotification.CanBeSentAt(testNotificationName, true, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cansend).To(BeTrue())
+			Expect(deferredUntil).To(BeZero())
+		})
+
+		It("sends a notification whose escalated severity bypasses the schedule", func() {
+			testManagedNotification.Spec.Notifications[0].Escalation = &v1alpha1.EscalationPolicy{
+				AfterSends: ptr.To(int32(1)),
+				Severity:   v1alpha1.SeverityCritical,
+			}
+			testManagedNotification.Status.NotificationRecords = []v1alpha1.NotificationRecord{
+				{Name: testNotificationName, ServiceLogSentCount: 1},
+			}
+			cansend, deferredUntil, err := testManagedNotification.CanBeSentAt(testNotificationName, true, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cansend).To(BeTrue())
+			Expect(deferredUntil).To(BeZero())
+		})
+
+		It("does not send a deferred notification", func() {
+			cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cansend).To(BeFalse())
+		})
+
+		It("does not defer a notification that cannot be sent anyway", func() {
+			cansend, deferredUntil, err := testManagedNotification.CanBeSentAt(testNotificationName, false, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cansend).To(BeFalse())
+			Expect(deferredUntil).To(BeZero())
+		})
+	})
+
 	Context("When checking if a resolved notifcation can be sent", func() {
 		When("there is no history for the notification", func() {
 			BeforeEach(func() {
 				testManagedNotification.Status.NotificationRecords = []v1alpha1.NotificationRecord{}
 			})
 			It("will not send", func() {
-				cansend, err := testManagedNotification.CanBeSent(testNotificationName, false)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, false)
 				Expect(cansend).To(BeFalse())
 				Expect(err).To(BeNil())
 			})
@@ -222,7 +355,7 @@ var _ = Describe("OCMAgent Controller", func() {
 
 		When("the resolved body is empty", func() {
 			It("will not send", func() {
-				cansend, err := testManagedNotificationWrb.CanBeSent(testNotificationNameWrb, false)
+				cansend, err := testManagedNotificationWrb.CanBeSentWithClock(fakeClock, testNotificationNameWrb, false)
 				Expect(cansend).To(BeFalse())
 				Expect(err).To(BeNil())
 			})
@@ -237,14 +370,14 @@ var _ = Describe("OCMAgent Controller", func() {
 								Type:               v1alpha1.ConditionAlertFiring,
 								Status:             corev1.ConditionFalse,
 								Reason:             "whatever",
-								LastTransitionTime: &metav1.Time{Time: time.Now()},
+								LastTransitionTime: &metav1.Time{Time: now},
 							},
 						},
 					},
 				}
 			})
 			It("will not send", func() {
-				cansend, err := testManagedNotification.CanBeSent(testNotificationName, false)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, false)
 				Expect(cansend).To(BeFalse())
 				Expect(err).To(BeNil())
 			})
@@ -252,7 +385,7 @@ var _ = Describe("OCMAgent Controller", func() {
 
 		When("the alert is already firing", func() {
 			It("will send the resolved notification", func() {
-				cansend, err := testManagedNotification.CanBeSent(testNotificationName, false)
+				cansend, err := testManagedNotification.CanBeSentWithClock(fakeClock, testNotificationName, false)
 				Expect(cansend).To(BeTrue())
 				Expect(err).To(BeNil())
 			})
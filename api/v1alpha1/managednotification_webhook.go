@@ -0,0 +1,64 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-ocmagent-managed-openshift-io-v1alpha1-managednotification,mutating=false,failurePolicy=fail,sideEffects=None,groups=ocmagent.managed.openshift.io,resources=managednotifications,verbs=create;update,versions=v1alpha1,name=vmanagednotification.ocmagent.managed.openshift.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the ManagedNotification validating webhook with the manager
+func (m *ManagedNotification) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, m).
+		WithValidator(&managedNotificationValidator{}).
+		Complete()
+}
+
+// managedNotificationValidator rejects ManagedNotifications that OCM Agent could not send
+type managedNotificationValidator struct{}
+
+var _ admission.Validator[*ManagedNotification] = &managedNotificationValidator{}
+
+// ValidateCreate implements admission.Validator
+func (v *managedNotificationValidator) ValidateCreate(_ context.Context, m *ManagedNotification) (admission.Warnings, error) {
+	return nil, validateManagedNotification(m)
+}
+
+// ValidateUpdate implements admission.Validator
+func (v *managedNotificationValidator) ValidateUpdate(_ context.Context, old, m *ManagedNotification) (admission.Warnings, error) {
+	if !updateNeedsValidation(m, old.Spec, m.Spec) {
+		return nil, nil
+	}
+	return nil, validateManagedNotification(m)
+}
+
+// ValidateDelete implements admission.Validator
+func (v *managedNotificationValidator) ValidateDelete(_ context.Context, _ *ManagedNotification) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateManagedNotification(m *ManagedNotification) error {
+	if errs := m.Validate(); len(errs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("ManagedNotification").GroupKind(), m.Name, errs)
+	}
+	return nil
+}
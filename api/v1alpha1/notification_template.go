@@ -0,0 +1,321 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// NotificationTemplateData is the alert a notification text is rendered for. The text refers to it as
+// the dot of a Go template, for example {{ .Labels.namespace }} or {{ .StartsAt.Format "2006-01-02" }}.
+// +kubebuilder:object:generate=false
+type NotificationTemplateData struct {
+	// The labels of the alert
+	Labels map[string]string
+	// The annotations of the alert
+	Annotations map[string]string
+	// The time the alert started firing
+	StartsAt time.Time
+	// The time the alert resolved, zero while it is firing
+	EndsAt time.Time
+}
+
+// notificationTemplateFields are the fields of NotificationTemplateData a notification text can refer to,
+// and whether they are maps whose keys can follow them
+var notificationTemplateFields = map[string]bool{
+	"Labels":      true,
+	"Annotations": true,
+	"StartsAt":    false,
+	"EndsAt":      false,
+}
+
+// maxRenderedNotificationLength is the most bytes a notification text renders to. Rendering a longer
+// text fails instead of growing without bound.
+const maxRenderedNotificationLength = 64 * 1024
+
+// RenderNotificationTemplate renders a notification text for the alert. A label or annotation the alert
+// does not have renders as an empty string, and a text without template actions is returned unchanged.
+func RenderNotificationTemplate(text string, data NotificationTemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := parseNotificationTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := executeNotificationTemplate(tmpl, &b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// sampleNotificationTemplateData is the alert a notification text is rendered for when it is validated
+var sampleNotificationTemplateData = NotificationTemplateData{
+	Labels:      map[string]string{"alertname": "ExampleAlert", "namespace": "openshift-example"},
+	Annotations: map[string]string{"summary": "Example summary", "description": "Example description"},
+	StartsAt:    time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+	EndsAt:      time.Date(2024, time.January, 1, 1, 0, 0, 0, time.UTC),
+}
+
+// ValidateNotificationTemplate checks that a notification text is a valid template that only refers to
+// the fields of the alert. The fields are checked without rendering the text, so that the branches the
+// alert does not take are checked as well, and the text is then rendered for a sample alert to catch
+// the errors only found when it is executed, such as an undefined template or wrong function arguments.
+func ValidateNotificationTemplate(text string) error {
+	if !strings.Contains(text, "{{") {
+		return nil
+	}
+
+	tmpl, err := parseNotificationTemplate(text)
+	if err != nil {
+		return err
+	}
+	if err := validateTemplateNode(tmpl.Tree.Root, true); err != nil {
+		return err
+	}
+	return executeNotificationTemplate(tmpl, io.Discard, sampleNotificationTemplateData)
+}
+
+// parseNotificationTemplate parses a notification text and checks that it renders in bounded time. A text
+// can only range over the labels or annotations of the alert, not within another range, and cannot define
+// or invoke templates.
+func parseNotificationTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("notification").
+		Option("missingkey=zero").
+		Funcs(template.FuncMap{"printf": boundedPrintf}).
+		Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpl.Templates()) > 1 {
+		return nil, fmt.Errorf("defining templates is not supported")
+	}
+	if err := boundTemplateNode(tmpl.Tree.Root, false); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// executeNotificationTemplate renders a parsed notification text for the alert, failing once the text
+// renders to more than maxRenderedNotificationLength bytes
+func executeNotificationTemplate(tmpl *template.Template, w io.Writer, data NotificationTemplateData) error {
+	return tmpl.Execute(&limitedWriter{w: w, remaining: maxRenderedNotificationLength}, data)
+}
+
+// limitedWriter writes to w until remaining bytes have been written, and fails the writes past them
+type limitedWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > l.remaining {
+		return 0, fmt.Errorf("the rendered text is longer than %d bytes", maxRenderedNotificationLength)
+	}
+	l.remaining -= len(p)
+	return l.w.Write(p)
+}
+
+// boundTemplateNode checks the actions of the node that could keep a text rendering without bound, such
+// as a range over an integer or a template invoking itself
+func boundTemplateNode(node parse.Node, inRange bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := boundTemplateNode(child, inRange); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return boundTemplateBranch(&n.BranchNode, inRange, inRange)
+	case *parse.WithNode:
+		return boundTemplateBranch(&n.BranchNode, inRange, inRange)
+	case *parse.RangeNode:
+		if inRange {
+			return fmt.Errorf("range within another range is not supported")
+		}
+		if !rangesOverAlertMap(n.Pipe) {
+			return fmt.Errorf("range over %q is not supported, only over .Labels or .Annotations", n.Pipe)
+		}
+		return boundTemplateBranch(&n.BranchNode, true, false)
+	case *parse.TemplateNode:
+		return fmt.Errorf("invoking templates is not supported")
+	}
+	return nil
+}
+
+// boundTemplateBranch checks the body and else branch of an if, range or with action
+func boundTemplateBranch(n *parse.BranchNode, bodyInRange, elseInRange bool) error {
+	if err := boundTemplateNode(n.List, bodyInRange); err != nil {
+		return err
+	}
+	return boundTemplateNode(n.ElseList, elseInRange)
+}
+
+// rangesOverAlertMap reports whether the pipeline of a range action is only .Labels or .Annotations of
+// the alert, which are the only values a range can iterate over
+func rangesOverAlertMap(pipe *parse.PipeNode) bool {
+	if len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	var ident []string
+	switch arg := pipe.Cmds[0].Args[0].(type) {
+	case *parse.FieldNode:
+		ident = arg.Ident
+	case *parse.VariableNode:
+		if arg.Ident[0] != "$" {
+			return false
+		}
+		ident = arg.Ident[1:]
+	default:
+		return false
+	}
+	return len(ident) == 1 && notificationTemplateFields[ident[0]]
+}
+
+// boundedPrintf is the printf function of a notification text. It rejects the widths and precisions,
+// such as %999999999d, that would allocate more than a rendered text can hold before it is written.
+func boundedPrintf(format string, args ...any) (string, error) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// The flags, argument indexes, width and precision run until the verb
+		digits := 0
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.[]*", format[i]) >= 0; i++ {
+			switch {
+			case format[i] == '*':
+				return "", fmt.Errorf("printf widths and precisions taken from arguments are not supported")
+			case format[i] >= '0' && format[i] <= '9':
+				digits = digits*10 + int(format[i]-'0')
+				if digits > maxRenderedNotificationLength {
+					return "", fmt.Errorf("printf widths and precisions above %d are not supported", maxRenderedNotificationLength)
+				}
+			default:
+				digits = 0
+			}
+		}
+	}
+	return fmt.Sprintf(format, args...), nil
+}
+
+// validateTemplateNode checks that the fields the node refers to exist in the alert. The dot is only
+// known to be the alert outside of the bodies of range and with actions, while $ is the alert everywhere.
+func validateTemplateNode(node parse.Node, dotIsAlert bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := validateTemplateNode(child, dotIsAlert); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return validateTemplateNode(n.Pipe, dotIsAlert)
+	case *parse.IfNode:
+		return validateTemplateBranch(&n.BranchNode, dotIsAlert, dotIsAlert)
+	case *parse.RangeNode:
+		return validateTemplateBranch(&n.BranchNode, dotIsAlert, false)
+	case *parse.WithNode:
+		return validateTemplateBranch(&n.BranchNode, dotIsAlert, false)
+	case *parse.TemplateNode:
+		return validateTemplateNode(n.Pipe, dotIsAlert)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := validateTemplateNode(cmd, dotIsAlert); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if err := validateTemplateNode(arg, dotIsAlert); err != nil {
+				return err
+			}
+		}
+	case *parse.ChainNode:
+		return validateTemplateNode(n.Node, dotIsAlert)
+	case *parse.FieldNode:
+		if dotIsAlert {
+			return validateTemplateField(n.Ident)
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			return validateTemplateField(n.Ident[1:])
+		}
+	}
+	return nil
+}
+
+// validateTemplateBranch checks the pipeline of an if, range or with action and its branches. The else
+// branch keeps the dot of the action, while the body keeps it only for if actions.
+func validateTemplateBranch(n *parse.BranchNode, dotIsAlert, bodyDotIsAlert bool) error {
+	if err := validateTemplateNode(n.Pipe, dotIsAlert); err != nil {
+		return err
+	}
+	if err := validateTemplateNode(n.List, bodyDotIsAlert); err != nil {
+		return err
+	}
+	return validateTemplateNode(n.ElseList, dotIsAlert)
+}
+
+// validateTemplateField checks that a field chain such as .Labels.namespace refers to the alert
+func validateTemplateField(ident []string) error {
+	isMap, ok := notificationTemplateFields[ident[0]]
+	if !ok {
+		return fmt.Errorf("unknown field %q, must be one of .Labels, .Annotations, .StartsAt or .EndsAt", "."+ident[0])
+	}
+	if isMap {
+		if len(ident) > 2 {
+			return fmt.Errorf("field %q refers past the value of %q", "."+strings.Join(ident, "."), "."+strings.Join(ident[:2], "."))
+		}
+		return nil
+	}
+
+	// The times can be followed by their methods, such as .StartsAt.UTC.Format
+	t := reflect.TypeFor[time.Time]()
+	for _, name := range ident[1:] {
+		m, ok := t.MethodByName(name)
+		if !ok || m.Type.NumOut() == 0 {
+			return fmt.Errorf("%s has no method %q", t, name)
+		}
+		t = m.Type.Out(0)
+	}
+	return nil
+}
+
+// RenderBody renders the body of the notification for the alert, the active body while it is firing
+// and the resolved body once it resolved. The body is returned as written unless the notification is templated.
+func (n *Notification) RenderBody(firing bool, data NotificationTemplateData) (string, error) {
+	body := n.ResolvedDesc
+	if firing {
+		body = n.ActiveDesc
+	}
+	if !n.Templated {
+		return body, nil
+	}
+	return RenderNotificationTemplate(body, data)
+}
+
+// RenderMessage renders the message of the fleet notification for the alert. The message is returned as
+// written unless the fleet notification is templated.
+func (n *FleetNotification) RenderMessage(data NotificationTemplateData) (string, error) {
+	if !n.Templated {
+		return n.NotificationMessage, nil
+	}
+	return RenderNotificationTemplate(n.NotificationMessage, data)
+}
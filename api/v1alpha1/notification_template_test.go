@@ -0,0 +1,130 @@
+package v1alpha1_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+var _ = Describe("Notification Templates", func() {
+
+	var data v1alpha1.NotificationTemplateData
+
+	BeforeEach(func() {
+		data = v1alpha1.NotificationTemplateData{
+			Labels:      map[string]string{"namespace": "openshift-monitoring", "node": "worker-1"},
+			Annotations: map[string]string{"description": "disk is almost full"},
+			StartsAt:    time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC),
+		}
+	})
+
+	Context("Rendering a notification text", func() {
+		It("substitutes the labels, annotations and times of the alert", func() {
+			text, err := v1alpha1.RenderNotificationTemplate(
+				`{{ .Labels.namespace }} on {{ .Labels.node }}: {{ .Annotations.description }} since {{ .StartsAt.Format "2006-01-02 15:04" }}`, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(Equal("openshift-monitoring on worker-1: disk is almost full since 2026-10-14 09:00"))
+		})
+		It("renders a label the alert does not have as empty", func() {
+			text, err := v1alpha1.RenderNotificationTemplate(`pod "{{ .Labels.pod }}"`, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(Equal(`pod ""`))
+		})
+		It("supports conditions on the alert", func() {
+			text, err := v1alpha1.RenderNotificationTemplate(`{{ if .EndsAt.IsZero }}firing{{ else }}resolved{{ end }}`, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(Equal("firing"))
+		})
+		It("returns a text without template actions unchanged", func() {
+			text, err := v1alpha1.RenderNotificationTemplate("Your cluster needs attention", data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(text).To(Equal("Your cluster needs attention"))
+		})
+		It("errors on an invalid template", func() {
+			_, err := v1alpha1.RenderNotificationTemplate("{{ .Labels.namespace ", data)
+			Expect(err).To(HaveOccurred())
+		})
+		It("renders the body for the state of the alert", func() {
+			n := v1alpha1.Notification{ActiveDesc: "{{ .Labels.node }} is down", ResolvedDesc: "{{ .Labels.node }} is back", Templated: true}
+			body, err := n.RenderBody(true, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(Equal("worker-1 is down"))
+			body, err = n.RenderBody(false, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(Equal("worker-1 is back"))
+		})
+		It("sends the body as written when the notification is not templated", func() {
+			n := v1alpha1.Notification{ActiveDesc: "Run oc get pods -o go-template='{{ .metadata.name }}'"}
+			body, err := n.RenderBody(true, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(Equal("Run oc get pods -o go-template='{{ .metadata.name }}'"))
+		})
+		It("stops rendering a text longer than the limit", func() {
+			labels := map[string]string{}
+			for i := 0; i < 100; i++ {
+				labels[fmt.Sprintf("label%d", i)] = strings.Repeat("x", 1024)
+			}
+			data.Labels = labels
+			_, err := v1alpha1.RenderNotificationTemplate("{{ range .Labels }}{{ . }}{{ end }}", data)
+			Expect(err).To(MatchError(ContainSubstring("longer than")))
+		})
+	})
+
+	Context("Validating a notification text", func() {
+		It("accepts the fields of the alert", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate(
+				`{{ .Labels.namespace }} {{ index .Annotations "summary" }} {{ .StartsAt.UTC.Format "15:04" }} {{ .EndsAt }}`)).To(Succeed())
+		})
+		It("accepts a text without template actions", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate("Your cluster needs attention")).To(Succeed())
+		})
+		It("rejects a text that does not parse", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ .Labels.namespace ")).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ upper .Labels.namespace }}")).NotTo(Succeed())
+		})
+		It("rejects an unknown field, even in a branch the alert would not take", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ .Cluster }}")).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ if .Labels.node }}ok{{ else }}{{ .Node }}{{ end }}")).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ range .Labels }}{{ $.Node }}{{ end }}")).NotTo(Succeed())
+		})
+		It("rejects a field past the value of a label", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ .Labels.node.name }}")).NotTo(Succeed())
+		})
+		It("rejects an unknown method of a time", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ .StartsAt.Ago }}")).NotTo(Succeed())
+		})
+		It("rejects a text that fails to render", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate(`{{ template "nope" }}`)).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ .StartsAt.Format }}")).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate(`{{ index .Labels "a" "b" }}`)).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ call .Labels }}")).NotTo(Succeed())
+		})
+		It("accepts the fields of the elements in the body of a range", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ range $k, $v := .Labels }}{{ $k }}={{ $v }} {{ end }}")).To(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ with .Labels.node }}{{ range $.Annotations }}{{ . }}{{ end }}{{ end }}")).To(Succeed())
+		})
+		It("rejects a range over anything but the labels or annotations", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ range 100000000000 }}x{{ end }}")).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ $n := 100000000000 }}{{ range $n }}x{{ end }}")).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate("{{ with .Labels }}{{ range . }}x{{ end }}{{ end }}")).NotTo(Succeed())
+		})
+		It("rejects a range within another range", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate(
+				"{{ range .Labels }}{{ range $.Annotations }}x{{ end }}{{ end }}")).NotTo(Succeed())
+		})
+		It("rejects defining or invoking templates", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate(`{{ define "loop" }}{{ template "loop" }}{{ end }}`)).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate(`{{ block "loop" . }}x{{ end }}`)).NotTo(Succeed())
+		})
+		It("rejects a printf width too large to render", func() {
+			Expect(v1alpha1.ValidateNotificationTemplate(`{{ printf "%999999999d" 1 }}`)).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate(`{{ printf "%.*f" 999999999 1.0 }}`)).NotTo(Succeed())
+			Expect(v1alpha1.ValidateNotificationTemplate(`{{ printf "%-20s|%5.2f" .Labels.node 1.0 }}`)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/openshift/ocm-agent-operator/api/v1beta1"
+)
+
+var _ conversion.Convertible = &OcmAgent{}
+
+// ConvertTo converts this OcmAgent to the hub version
+func (o *OcmAgent) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.OcmAgent)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", dstRaw)
+	}
+	// Convert a copy, so that the converted object shares no maps, slices or pointers with this one
+	src := o.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.AgentConfig.OcmBaseUrl = src.Spec.AgentConfig.OcmBaseUrl
+	dst.Spec.AgentConfig.Services = nil
+	for _, s := range src.Spec.AgentConfig.Services {
+		dst.Spec.AgentConfig.Services = append(dst.Spec.AgentConfig.Services, v1beta1.OcmService(s))
+	}
+	dst.Spec.OcmAgentImage = src.Spec.OcmAgentImage
+	dst.Spec.TokenSecret = src.Spec.TokenSecret
+	dst.Spec.Replicas = src.Spec.Replicas
+	if src.Spec.FleetMode {
+		dst.Spec.Mode = v1beta1.OcmAgentModeFleet
+	} else {
+		dst.Spec.Mode = v1beta1.OcmAgentModeCluster
+	}
+	dst.Spec.Resources = src.Spec.Resources
+	dst.Spec.NodeSelector = src.Spec.NodeSelector
+	dst.Spec.Tolerations = src.Spec.Tolerations
+	dst.Spec.Affinity = src.Spec.Affinity
+	dst.Spec.TopologySpreadConstraints = src.Spec.TopologySpreadConstraints
+	dst.Spec.PriorityClassName = src.Spec.PriorityClassName
+
+	dst.Status.ServiceStatus = src.Status.ServiceStatus
+	dst.Status.AvailableReplicas = src.Status.AvailableReplicas
+	dst.Status.ObservedGeneration = src.Status.ObservedGeneration
+	dst.Status.Conditions = src.Status.Conditions
+
+	return nil
+}
+
+// ConvertFrom converts the hub version to this OcmAgent
+func (o *OcmAgent) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.OcmAgent)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub type %T", srcRaw)
+	}
+	// Convert a copy, so that this object shares no maps, slices or pointers with the hub
+	src = src.DeepCopy()
+
+	o.ObjectMeta = src.ObjectMeta
+
+	o.Spec.AgentConfig.OcmBaseUrl = src.Spec.AgentConfig.OcmBaseUrl
+	o.Spec.AgentConfig.Services = nil
+	for _, s := range src.Spec.AgentConfig.Services {
+		o.Spec.AgentConfig.Services = append(o.Spec.AgentConfig.Services, string(s))
+	}
+	o.Spec.OcmAgentImage = src.Spec.OcmAgentImage
+	o.Spec.TokenSecret = src.Spec.TokenSecret
+	o.Spec.Replicas = src.Spec.Replicas
+	o.Spec.FleetMode = src.Spec.Mode == v1beta1.OcmAgentModeFleet
+	o.Spec.Resources = src.Spec.Resources
+	o.Spec.NodeSelector = src.Spec.NodeSelector
+	o.Spec.Tolerations = src.Spec.Tolerations
+	o.Spec.Affinity = src.Spec.Affinity
+	o.Spec.TopologySpreadConstraints = src.Spec.TopologySpreadConstraints
+	o.Spec.PriorityClassName = src.Spec.PriorityClassName
+
+	o.Status.ServiceStatus = src.Status.ServiceStatus
+	o.Status.AvailableReplicas = src.Status.AvailableReplicas
+	o.Status.ObservedGeneration = src.Status.ObservedGeneration
+	o.Status.Conditions = src.Status.Conditions
+
+	return nil
+}
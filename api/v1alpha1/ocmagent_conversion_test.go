@@ -0,0 +1,108 @@
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	"github.com/openshift/ocm-agent-operator/api/v1beta1"
+)
+
+var _ = Describe("OcmAgent Conversion", func() {
+	var testOcmAgent *v1alpha1.OcmAgent
+
+	BeforeEach(func() {
+		testOcmAgent = &v1alpha1.OcmAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "ocmagent",
+				Namespace:  "test-ns",
+				Generation: 2,
+			},
+			Spec: v1alpha1.OcmAgentSpec{
+				AgentConfig: v1alpha1.AgentConfig{
+					OcmBaseUrl: "https://api.openshift.com",
+					Services:   []string{v1alpha1.OcmServiceServiceLogs, v1alpha1.OcmServiceClustersMgmt},
+				},
+				OcmAgentImage: "quay.io/app-sre/ocm-agent:latest",
+				TokenSecret:   "ocm-access-token",
+				Replicas:      2,
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+				NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+				Tolerations:       []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+				PriorityClassName: "system-cluster-critical",
+			},
+			Status: v1alpha1.OcmAgentStatus{
+				ServiceStatus:      v1alpha1.OcmAgentServiceStatusAvailable,
+				AvailableReplicas:  2,
+				ObservedGeneration: 2,
+				Conditions: []metav1.Condition{
+					{Type: v1alpha1.OcmAgentConditionAvailable, Status: metav1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+				},
+			},
+		}
+	})
+
+	Context("When converting to v1beta1", func() {
+		It("converts the services and the cluster mode", func() {
+			hub := &v1beta1.OcmAgent{}
+			Expect(testOcmAgent.ConvertTo(hub)).To(Succeed())
+			Expect(hub.ObjectMeta).To(Equal(testOcmAgent.ObjectMeta))
+			Expect(hub.Spec.AgentConfig.Services).To(Equal([]v1beta1.OcmService{v1beta1.OcmServiceServiceLogs, v1beta1.OcmServiceClustersMgmt}))
+			Expect(hub.Spec.Mode).To(Equal(v1beta1.OcmAgentModeCluster))
+			Expect(hub.Spec.Replicas).To(Equal(int32(2)))
+			Expect(hub.Spec.Resources).To(Equal(testOcmAgent.Spec.Resources))
+			Expect(hub.Spec.NodeSelector).To(Equal(testOcmAgent.Spec.NodeSelector))
+			Expect(hub.Spec.Tolerations).To(Equal(testOcmAgent.Spec.Tolerations))
+			Expect(hub.Spec.PriorityClassName).To(Equal(testOcmAgent.Spec.PriorityClassName))
+			Expect(hub.Status.Conditions).To(Equal(testOcmAgent.Status.Conditions))
+		})
+
+		It("does not share the conditions with the converted object", func() {
+			hub := &v1beta1.OcmAgent{}
+			Expect(testOcmAgent.ConvertTo(hub)).To(Succeed())
+			hub.Status.Conditions[0].Status = metav1.ConditionFalse
+			Expect(testOcmAgent.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("converts fleet mode", func() {
+			testOcmAgent.Spec.FleetMode = true
+			hub := &v1beta1.OcmAgent{}
+			Expect(testOcmAgent.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Mode).To(Equal(v1beta1.OcmAgentModeFleet))
+		})
+	})
+
+	Context("When converting from v1beta1", func() {
+		It("round trips without losing data", func() {
+			for _, fleetMode := range []bool{false, true} {
+				testOcmAgent.Spec.FleetMode = fleetMode
+				hub := &v1beta1.OcmAgent{}
+				Expect(testOcmAgent.ConvertTo(hub)).To(Succeed())
+				converted := &v1alpha1.OcmAgent{}
+				Expect(converted.ConvertFrom(hub)).To(Succeed())
+				Expect(converted).To(Equal(testOcmAgent))
+			}
+		})
+
+		It("does not share the conditions with the hub", func() {
+			hub := &v1beta1.OcmAgent{}
+			Expect(testOcmAgent.ConvertTo(hub)).To(Succeed())
+			converted := &v1alpha1.OcmAgent{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			converted.Status.Conditions[0].Status = metav1.ConditionFalse
+			Expect(hub.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("treats an unset mode as cluster mode", func() {
+			hub := &v1beta1.OcmAgent{Spec: v1beta1.OcmAgentSpec{Replicas: 1}}
+			converted := &v1alpha1.OcmAgent{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+			Expect(converted.Spec.FleetMode).To(BeFalse())
+		})
+	})
+})
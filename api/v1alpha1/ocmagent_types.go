@@ -17,22 +17,35 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// OcmServiceServiceLogs is the OCM service used to send service logs
+	OcmServiceServiceLogs = "service_logs"
+	// OcmServiceClustersMgmt is the OCM service used to manage clusters and their upgrade policies
+	OcmServiceClustersMgmt = "clusters_mgmt"
 )
 
+// SupportedOcmServices lists the OCM services that the OCM Agent can proxy
+var SupportedOcmServices = []string{OcmServiceServiceLogs, OcmServiceClustersMgmt}
+
 type AgentConfig struct {
 	// OcmBaseUrl defines the OCM api endpoint for OCM agent to access
 	OcmBaseUrl string `json:"ocmBaseUrl"`
 
-	// Services defines the supported OCM services, eg, service_log, cluster_management
+	// Services defines the supported OCM services, eg, service_logs, clusters_mgmt
 	Services []string `json:"services"`
 }
 
 // OcmAgentSpec defines the desired state of OcmAgent
 type OcmAgentSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-
 	// AgentConfig refers to OCM agent config fields separated
 	AgentConfig AgentConfig `json:"agentConfig"`
 
@@ -47,22 +60,95 @@ type OcmAgentSpec struct {
 
 	// FleetMode indicates if the OCM agent is running in fleet mode, default to false
 	FleetMode bool `json:"fleetMode,omitempty"`
+
+	// Resources overrides the default resource requests and limits of the OCM Agent container.
+	// Only the resources that are set are overridden, the others keep their defaults, except that
+	// a default limit below a request that is set is raised to the request, and a default request
+	// above a limit that is set is lowered to the limit.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector constrains the OCM Agent pods to nodes with matching labels
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are added to the default toleration of the infra node taint
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity replaces the default node affinity and pod anti-affinity of the OCM Agent pods,
+	// for each type of affinity that is set
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// TopologySpreadConstraints describes how the OCM Agent pods are spread across topology domains
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// PriorityClassName is the name of the priority class of the OCM Agent pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
+const (
+	// OcmAgentConditionAvailable indicates that the OCM Agent deployment has at least one available replica
+	OcmAgentConditionAvailable = "Available"
+	// OcmAgentConditionProgressing indicates that the OCM Agent deployment is rolling out a change
+	OcmAgentConditionProgressing = "Progressing"
+	// OcmAgentConditionDegraded indicates that the operator failed to reconcile the OCM Agent resources
+	OcmAgentConditionDegraded = "Degraded"
+	// OcmAgentConditionPullSecretValid indicates that the OCM access token could be sourced from the cluster pull secret
+	OcmAgentConditionPullSecretValid = "PullSecretValid"
+	// OcmAgentConditionProxyConfigured indicates that the cluster proxy settings were applied to the OCM Agent deployment
+	OcmAgentConditionProxyConfigured = "ProxyConfigured"
+	// OcmAgentConditionPaused indicates that the reconciliation of the OCM Agent resources is suspended
+	OcmAgentConditionPaused = "Paused"
+)
+
+const (
+	// PausedAnnotation suspends the reconciliation of the OCM Agent resources when set to "true" on an OcmAgent
+	PausedAnnotation = "ocmagent.managed.openshift.io/paused"
+	// UnmanagedAnnotation stops the operator from changing or deleting an OCM Agent resource when set to "true" on it
+	UnmanagedAnnotation = "ocmagent.managed.openshift.io/unmanaged"
+)
+
+const (
+	// OcmAgentServiceStatusAvailable is the ServiceStatus of a healthy OCM Agent
+	OcmAgentServiceStatusAvailable = "Available"
+	// OcmAgentServiceStatusProgressing is the ServiceStatus of an OCM Agent that is still rolling out
+	OcmAgentServiceStatusProgressing = "Progressing"
+	// OcmAgentServiceStatusDegraded is the ServiceStatus of an OCM Agent that failed to reconcile
+	OcmAgentServiceStatusDegraded = "Degraded"
+	// OcmAgentServiceStatusUnavailable is the ServiceStatus of an OCM Agent with no available replicas
+	OcmAgentServiceStatusUnavailable = "Unavailable"
+)
+
 // OcmAgentStatus defines the observed state of OcmAgent
 type OcmAgentStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-
 	// ServiceStatus indicates the status of OCM Agent service
 	ServiceStatus string `json:"serviceStatus"`
 
+	// AvailableReplicas is the number of available replicas of the OCM Agent deployment
 	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// ObservedGeneration is the most recent generation of the OcmAgent observed by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the OCM Agent state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 //+kubebuilder:resource:path=ocmagents,scope=Namespaced
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.serviceStatus`
+//+kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableReplicas`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // OcmAgent is the Schema for the ocmagents API
 type OcmAgent struct {
@@ -85,3 +171,61 @@ type OcmAgentList struct {
 func init() {
 	SchemeBuilder.Register(&OcmAgent{}, &OcmAgentList{})
 }
+
+// IsPaused reports whether the reconciliation of the OCM Agent resources is suspended with the paused annotation
+func (o *OcmAgent) IsPaused() bool {
+	return o.Annotations[PausedAnnotation] == "true"
+}
+
+// Validate checks the OcmAgent spec for problems the CRD schema cannot express and
+// returns every problem found.
+func (o *OcmAgent) Validate() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	baseURLPath := specPath.Child("agentConfig", "ocmBaseUrl")
+	if u, err := url.ParseRequestURI(o.Spec.AgentConfig.OcmBaseUrl); err != nil {
+		allErrs = append(allErrs, field.Invalid(baseURLPath, o.Spec.AgentConfig.OcmBaseUrl, err.Error()))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		allErrs = append(allErrs, field.Invalid(baseURLPath, o.Spec.AgentConfig.OcmBaseUrl,
+			fmt.Sprintf("unsupported scheme %q, must be http or https", u.Scheme)))
+	} else if u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(baseURLPath, o.Spec.AgentConfig.OcmBaseUrl, "OCM base URL must contain a host"))
+	}
+
+	for i, s := range o.Spec.AgentConfig.Services {
+		if !isSupportedOcmService(s) {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("agentConfig", "services").Index(i), s, SupportedOcmServices))
+		}
+	}
+
+	if o.Spec.Replicas < 1 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), o.Spec.Replicas, "replicas must be at least 1"))
+	}
+
+	if o.Spec.Resources != nil {
+		resourcesPath := specPath.Child("resources")
+		for name, request := range o.Spec.Resources.Requests {
+			if limit, ok := o.Spec.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+				allErrs = append(allErrs, field.Invalid(resourcesPath.Child("requests").Key(string(name)), request.String(),
+					fmt.Sprintf("must be less than or equal to the %s limit", name)))
+			}
+		}
+	}
+
+	if strings.TrimSpace(o.Spec.TokenSecret) == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("tokenSecret"), "token secret name must not be empty"))
+	}
+
+	return allErrs
+}
+
+// isSupportedOcmService reports whether the service is one the OCM Agent can proxy
+func isSupportedOcmService(service string) bool {
+	for _, s := range SupportedOcmServices {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
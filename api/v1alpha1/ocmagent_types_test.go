@@ -0,0 +1,96 @@
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+var _ = Describe("OcmAgent Type", func() {
+	var testOcmAgent *v1alpha1.OcmAgent
+
+	BeforeEach(func() {
+		testOcmAgent = &v1alpha1.OcmAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ocmagent",
+				Namespace: "test-ns",
+			},
+			Spec: v1alpha1.OcmAgentSpec{
+				AgentConfig: v1alpha1.AgentConfig{
+					OcmBaseUrl: "https://api.openshift.com",
+					Services:   []string{v1alpha1.OcmServiceServiceLogs, v1alpha1.OcmServiceClustersMgmt},
+				},
+				OcmAgentImage: "quay.io/app-sre/ocm-agent:latest",
+				TokenSecret:   "ocm-access-token",
+				Replicas:      1,
+			},
+		}
+	})
+
+	Context("When validating an OcmAgent", func() {
+		It("accepts a valid spec", func() {
+			Expect(testOcmAgent.Validate()).To(BeEmpty())
+		})
+
+		It("rejects an unparsable OCM base URL", func() {
+			testOcmAgent.Spec.AgentConfig.OcmBaseUrl = "api.openshift.com"
+			errs := testOcmAgent.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errs[0].Field).To(Equal("spec.agentConfig.ocmBaseUrl"))
+		})
+
+		It("rejects an OCM base URL with an unsupported scheme", func() {
+			testOcmAgent.Spec.AgentConfig.OcmBaseUrl = "ftp://api.openshift.com"
+			errs := testOcmAgent.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.agentConfig.ocmBaseUrl"))
+		})
+
+		It("rejects unknown services", func() {
+			testOcmAgent.Spec.AgentConfig.Services = []string{v1alpha1.OcmServiceServiceLogs, "cluster_management"}
+			errs := testOcmAgent.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+			Expect(errs[0].Field).To(Equal("spec.agentConfig.services[1]"))
+		})
+
+		It("rejects less than one replica", func() {
+			testOcmAgent.Spec.Replicas = 0
+			errs := testOcmAgent.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.replicas"))
+		})
+
+		It("accepts resource overrides", func() {
+			testOcmAgent.Spec.Resources = &corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			}
+			Expect(testOcmAgent.Validate()).To(BeEmpty())
+		})
+
+		It("rejects a resource request above its limit", func() {
+			testOcmAgent.Spec.Resources = &corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}
+			errs := testOcmAgent.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.resources.requests[memory]"))
+		})
+
+		It("rejects an empty token secret", func() {
+			testOcmAgent.Spec.TokenSecret = ""
+			errs := testOcmAgent.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+			Expect(errs[0].Field).To(Equal("spec.tokenSecret"))
+		})
+	})
+})
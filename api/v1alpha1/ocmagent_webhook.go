@@ -0,0 +1,77 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-ocmagent-managed-openshift-io-v1alpha1-ocmagent,mutating=false,failurePolicy=fail,sideEffects=None,groups=ocmagent.managed.openshift.io,resources=ocmagents,verbs=create;update,versions=v1alpha1,name=vocmagent.ocmagent.managed.openshift.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the OcmAgent validating webhook with the manager
+func (o *OcmAgent) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, o).
+		WithValidator(&ocmAgentValidator{}).
+		Complete()
+}
+
+// ocmAgentValidator rejects OcmAgents that the operator would be unable to deploy
+type ocmAgentValidator struct{}
+
+var _ admission.Validator[*OcmAgent] = &ocmAgentValidator{}
+
+// ValidateCreate implements admission.Validator
+func (v *ocmAgentValidator) ValidateCreate(_ context.Context, o *OcmAgent) (admission.Warnings, error) {
+	return nil, validateOcmAgent(o)
+}
+
+// ValidateUpdate implements admission.Validator
+func (v *ocmAgentValidator) ValidateUpdate(_ context.Context, old, o *OcmAgent) (admission.Warnings, error) {
+	if !updateNeedsValidation(o, old.Spec, o.Spec) {
+		return nil, nil
+	}
+	return nil, validateOcmAgent(o)
+}
+
+// ValidateDelete implements admission.Validator
+func (v *ocmAgentValidator) ValidateDelete(_ context.Context, _ *OcmAgent) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateOcmAgent(o *OcmAgent) error {
+	if errs := o.Validate(); len(errs) > 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("OcmAgent").GroupKind(), o.Name, errs)
+	}
+	return nil
+}
+
+// updateNeedsValidation reports whether an update of an object must be validated. Updates that leave
+// the spec unchanged, such as finalizer or label changes, and updates of an object being deleted are
+// let through, so that an object stored before a validation rule was added can still be migrated,
+// have its finalizers removed and be deleted.
+func updateNeedsValidation(obj metav1.Object, oldSpec, newSpec interface{}) bool {
+	if obj.GetDeletionTimestamp() != nil {
+		return false
+	}
+	return !equality.Semantic.DeepEqual(oldSpec, newSpec)
+}
@@ -5,6 +5,8 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -49,6 +51,73 @@ func (in Conditions) DeepCopy() Conditions {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliverySchedule) DeepCopyInto(out *DeliverySchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]DeliveryWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliverySchedule.
+func (in *DeliverySchedule) DeepCopy() *DeliverySchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliverySchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliveryWindow) DeepCopyInto(out *DeliveryWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryWindow.
+func (in *DeliveryWindow) DeepCopy() *DeliveryWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliveryWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationPolicy) DeepCopyInto(out *EscalationPolicy) {
+	*out = *in
+	if in.AfterSends != nil {
+		in, out := &in.AfterSends, &out.AfterSends
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AfterFiringHours != nil {
+		in, out := &in.AfterFiringHours, &out.AfterFiringHours
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationPolicy.
+func (in *EscalationPolicy) DeepCopy() *EscalationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FleetNotification) DeepCopyInto(out *FleetNotification) {
 	*out = *in
@@ -57,6 +126,21 @@ func (in *FleetNotification) DeepCopyInto(out *FleetNotification) {
 		*out = make([]NotificationReferenceType, len(*in))
 		copy(*out, *in)
 	}
+	if in.StaleTimeout != nil {
+		in, out := &in.StaleTimeout, &out.StaleTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSendsPerWindow != nil {
+		in, out := &in.MaxSendsPerWindow, &out.MaxSendsPerWindow
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetNotification.
@@ -75,6 +159,7 @@ func (in *ManagedFleetNotification) DeepCopyInto(out *ManagedFleetNotification)
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedFleetNotification.
@@ -223,6 +308,25 @@ func (in *ManagedFleetNotificationSpec) DeepCopy() *ManagedFleetNotificationSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedFleetNotificationStatus) DeepCopyInto(out *ManagedFleetNotificationStatus) {
+	*out = *in
+	if in.LastSentTime != nil {
+		in, out := &in.LastSentTime, &out.LastSentTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedFleetNotificationStatus.
+func (in *ManagedFleetNotificationStatus) DeepCopy() *ManagedFleetNotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedFleetNotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedNotification) DeepCopyInto(out *ManagedNotification) {
 	*out = *in
@@ -314,6 +418,13 @@ func (in *ManagedNotificationStatus) DeepCopyInto(out *ManagedNotificationStatus
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedNotificationStatus.
@@ -334,6 +445,16 @@ func (in *Notification) DeepCopyInto(out *Notification) {
 		*out = make([]NotificationReferenceType, len(*in))
 		copy(*out, *in)
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(DeliverySchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Escalation != nil {
+		in, out := &in.Escalation, &out.Escalation
+		*out = new(EscalationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notification.
@@ -390,6 +511,16 @@ func (in *NotificationRecord) DeepCopy() *NotificationRecord {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotificationRecordByName) DeepCopyInto(out *NotificationRecordByName) {
 	*out = *in
+	if in.MaxSendsPerWindow != nil {
+		in, out := &in.MaxSendsPerWindow, &out.MaxSendsPerWindow
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(int32)
+		**out = **in
+	}
 	if in.NotificationRecordItems != nil {
 		in, out := &in.NotificationRecordItems, &out.NotificationRecordItems
 		*out = make([]NotificationRecordItem, len(*in))
@@ -416,6 +547,13 @@ func (in *NotificationRecordItem) DeepCopyInto(out *NotificationRecordItem) {
 		in, out := &in.LastTransitionTime, &out.LastTransitionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.RecentSendTimes != nil {
+		in, out := &in.RecentSendTimes, &out.RecentSendTimes
+		*out = make([]v1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationRecordItem.
@@ -455,7 +593,7 @@ func (in *OcmAgent) DeepCopyInto(out *OcmAgent) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OcmAgent.
@@ -512,6 +650,37 @@ func (in *OcmAgentList) DeepCopyObject() runtime.Object {
 func (in *OcmAgentSpec) DeepCopyInto(out *OcmAgentSpec) {
 	*out = *in
 	in.AgentConfig.DeepCopyInto(&out.AgentConfig)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OcmAgentSpec.
@@ -527,6 +696,13 @@ func (in *OcmAgentSpec) DeepCopy() *OcmAgentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OcmAgentStatus) DeepCopyInto(out *OcmAgentStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OcmAgentStatus.
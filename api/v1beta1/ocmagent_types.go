@@ -0,0 +1,154 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OcmService is an OCM service that the OCM Agent proxies requests to
+// +kubebuilder:validation:Enum=service_logs;clusters_mgmt
+type OcmService string
+
+const (
+	// OcmServiceServiceLogs is the OCM service used to send service logs
+	OcmServiceServiceLogs OcmService = "service_logs"
+	// OcmServiceClustersMgmt is the OCM service used to manage clusters and their upgrade policies
+	OcmServiceClustersMgmt OcmService = "clusters_mgmt"
+)
+
+// OcmAgentMode is the mode the OCM Agent is deployed in
+// +kubebuilder:validation:Enum=Cluster;Fleet
+type OcmAgentMode string
+
+const (
+	// OcmAgentModeCluster deploys an OCM Agent serving the cluster it runs on
+	OcmAgentModeCluster OcmAgentMode = "Cluster"
+	// OcmAgentModeFleet deploys an OCM Agent serving the hosted clusters of a management cluster
+	OcmAgentModeFleet OcmAgentMode = "Fleet"
+)
+
+type AgentConfig struct {
+	// OcmBaseUrl defines the OCM api endpoint for OCM agent to access
+	// +kubebuilder:validation:Pattern=`^https?://`
+	OcmBaseUrl string `json:"ocmBaseUrl"`
+
+	// Services defines the OCM services the OCM agent proxies requests to
+	Services []OcmService `json:"services"`
+}
+
+// OcmAgentSpec defines the desired state of OcmAgent
+type OcmAgentSpec struct {
+	// AgentConfig refers to OCM agent config fields separated
+	AgentConfig AgentConfig `json:"agentConfig"`
+
+	// OcmAgentImage defines the image which will be used by the OCM Agent
+	OcmAgentImage string `json:"ocmAgentImage"`
+
+	// TokenSecret points to the secret name which stores the access token to OCM server
+	// +kubebuilder:validation:MinLength=1
+	TokenSecret string `json:"tokenSecret"`
+
+	// Replicas defines the replica count for the OCM Agent service
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas"`
+
+	// Mode defines whether the OCM agent serves the cluster it runs on or a fleet of hosted clusters
+	// +kubebuilder:default=Cluster
+	// +optional
+	Mode OcmAgentMode `json:"mode,omitempty"`
+
+	// Resources overrides the default resource requests and limits of the OCM Agent container.
+	// Only the resources that are set are overridden, the others keep their defaults, except that
+	// a default limit below a request that is set is raised to the request, and a default request
+	// above a limit that is set is lowered to the limit.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector constrains the OCM Agent pods to nodes with matching labels
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are added to the default toleration of the infra node taint
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity replaces the default node affinity and pod anti-affinity of the OCM Agent pods,
+	// for each type of affinity that is set
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// TopologySpreadConstraints describes how the OCM Agent pods are spread across topology domains
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// PriorityClassName is the name of the priority class of the OCM Agent pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// OcmAgentStatus defines the observed state of OcmAgent
+type OcmAgentStatus struct {
+	// ServiceStatus indicates the status of OCM Agent service
+	ServiceStatus string `json:"serviceStatus"`
+
+	// AvailableReplicas is the number of available replicas of the OCM Agent deployment
+	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// ObservedGeneration is the most recent generation of the OcmAgent observed by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the OCM Agent state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:unservedversion
+//+kubebuilder:resource:path=ocmagents,scope=Namespaced
+//+kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.serviceStatus`
+//+kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableReplicas`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// OcmAgent is the Schema for the ocmagents API. It is not served until the conversion webhook is
+// deployed with every installation of the operator, as the API server cannot convert it without it.
+type OcmAgent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OcmAgentSpec   `json:"spec,omitempty"`
+	Status OcmAgentStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OcmAgentList contains a list of OcmAgent
+type OcmAgentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OcmAgent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OcmAgent{}, &OcmAgentList{})
+}
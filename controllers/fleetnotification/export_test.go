@@ -0,0 +1,7 @@
+package fleetnotification
+
+// Expose the watch helpers to the external test package
+var (
+	EnqueueFleetNotificationsForRecord = (*ManagedFleetNotificationStatusReconciler).enqueueFleetNotificationsForRecord
+	EnqueueRecordsForFleetNotification = (*ManagedFleetNotificationReconciler).enqueueRecordsForFleetNotification
+)
@@ -18,29 +18,49 @@ package fleetnotification
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
 )
 
 const (
+	// NotificationRecordStaleTimeoutInHour is the default stale timeout of the notification records
 	NotificationRecordStaleTimeoutInHour int32 = 360
+	// NotificationRecordCleanupInterval is how often a record is checked for stale items when nothing else changes it
+	NotificationRecordCleanupInterval = time.Hour
 )
 
 // ManagedFleetNotificationReconciler reconciles a ManagedFleetNotification object
 type ManagedFleetNotificationReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// StaleTimeout is how long after the resend wait has elapsed a record item is kept before it is removed
+	// as stale, unless its ManagedFleetNotification sets its own. Defaults to NotificationRecordStaleTimeoutInHour.
+	StaleTimeout time.Duration
+	// Clock tells the time the record items are checked against. Defaults to the real clock.
+	Clock clock.PassiveClock
+	// ShardRecords enables moving the records of an unsharded ManagedFleetNotificationRecord to the record
+	// shard of each notification. It must only be enabled once the OCM Agent writes to the record shards,
+	// as the unsharded record is deleted once its records are moved.
+	ShardRecords bool
 }
 
 var log = logf.Log.WithName("controller_fleetnotification")
@@ -50,6 +70,8 @@ var _ reconcile.Reconciler = &ManagedFleetNotificationReconciler{}
 //+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=managedfleetnotifications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=managedfleetnotifications/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=managedfleetnotifications/finalizers,verbs=update
+//+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=managedfleetnotificationrecords,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=managedfleetnotificationrecords/status,verbs=get;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -69,58 +91,344 @@ func (r *ManagedFleetNotificationReconciler) Reconcile(ctx context.Context, requ
 
 	err := r.Get(ctx, request.NamespacedName, &nr)
 	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
 		return reconcile.Result{}, err
 	}
 
+	// Records holding more than one notification are split into shards, which are then reconciled on their own.
+	// Otherwise the unsharded record is kept, as the OCM Agent still reads and writes it.
+	if r.ShardRecords && !nr.IsNotificationRecordShard() && len(nr.Status.NotificationRecordByName) > 0 {
+		if err := r.migrateToShards(ctx, &nr); err != nil {
+			reqLogger.Error(err, "Failed to migrate the notification record to shards. Will retry on next reconcile.")
+			return ctrl.Result{}, err
+		}
+		reqLogger.Info("Migrated the notification record to shards", "notifications", len(nr.Status.NotificationRecordByName))
+		return ctrl.Result{}, nil
+	}
+
+	now := r.now()
+	updateRecordMetrics(&nr, now)
+
+	settings, err := r.notificationSettings(ctx, &nr)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The resend wait and send limit are copied from the ManagedFleetNotification when the record is
+	// created, so they are kept in line with later changes of the notification
+	driftedSettings := findSettingsDrift(&nr, settings)
+	if len(driftedSettings) > 0 {
+		err := r.patchRecordSettings(ctx, &nr, driftedSettings)
+		if err != nil {
+			reqLogger.Error(err, "Failed to update the settings of the notification records. Will retry on next reconcile.")
+			return ctrl.Result{}, err
+		}
+		for _, drift := range driftedSettings {
+			nr.Status.NotificationRecordByName[drift.nameSlot].SetFleetNotificationSettings(drift.notification)
+		}
+		reqLogger.Info("Updated the settings of the notification records", "count", len(driftedSettings))
+	}
+
+	staleItems := findStaleRecordItems(&nr, now, settings)
+	if len(staleItems) > 0 {
+		err := r.patchRemoveItems(ctx, &nr, staleItems)
+		if err != nil {
+			reqLogger.Error(err, "Failed to remove stale notification record items. Will retry on next reconcile.")
+			return ctrl.Result{}, err
+		}
+		for _, item := range staleItems {
+			localmetrics.UpdateMetricFleetNotificationRecordItemsRemoved(nr.Status.ManagementCluster, item.notificationName, item.reason)
+		}
+		reqLogger.Info("Removed stale notification record items", "count", len(staleItems))
+	}
+
+	// Items become stale without the record changing, so check it again later
+	return ctrl.Result{RequeueAfter: NotificationRecordCleanupInterval}, nil
+}
+
+// now returns the current time told by the clock of the reconciler
+func (r *ManagedFleetNotificationReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// staleRecordItem identifies a notification record item to remove and why
+type staleRecordItem struct {
+	nameSlot         int
+	itemSlot         int
+	notificationName string
+	hostedClusterID  string
+	reason           string
+}
+
+// notificationSettings holds the settings of a notification that are taken from its ManagedFleetNotification
+type notificationSettings struct {
+	staleTimeout time.Duration
+	// notification is nil when no ManagedFleetNotification defines the notification
+	notification *ocmagentv1alpha1.FleetNotification
+}
+
+// settingsDrift identifies notification records whose settings differ from their ManagedFleetNotification
+type settingsDrift struct {
+	nameSlot     int
+	current      ocmagentv1alpha1.NotificationRecordByName
+	notification ocmagentv1alpha1.FleetNotification
+}
+
+// migrateToShards moves the records of each notification of an unsharded record to the record shard
+// of the notification, and then deletes the unsharded record. Records already in a shard are kept
+// unless the unsharded record holds a later send, so the migration can be retried after a partial failure.
+// The unsharded record is only deleted at the version that was moved, and is moved again when the OCM
+// Agent updated it in the meantime.
+func (r *ManagedFleetNotificationReconciler) migrateToShards(ctx context.Context, nr *ocmagentv1alpha1.ManagedFleetNotificationRecord) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		for _, rn := range nr.Status.NotificationRecordByName {
+			shard, err := ocmagentv1alpha1.GetOrCreateNotificationRecordShard(ctx, r.Client, nr.Namespace, nr.Status.ManagementCluster, rn)
+			if err != nil {
+				return err
+			}
+			if shard.MergeNotificationRecordByName(rn) {
+				if err := r.Status().Update(ctx, shard); err != nil {
+					return err
+				}
+			}
+		}
+		err := r.Delete(ctx, nr, client.Preconditions{ResourceVersion: &nr.ResourceVersion})
+		if k8serrors.IsConflict(err) {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(nr), nr); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+		}
+		return client.IgnoreNotFound(err)
+	})
+}
+
+// notificationSettings returns the settings of each notification in the record, taken from the
+// ManagedFleetNotification of the same name. The stale timeout is the operator's default unless
+// the ManagedFleetNotification sets its own.
+func (r *ManagedFleetNotificationReconciler) notificationSettings(ctx context.Context, nr *ocmagentv1alpha1.ManagedFleetNotificationRecord) (map[string]notificationSettings, error) {
+	settings := make(map[string]notificationSettings, len(nr.Status.NotificationRecordByName))
+	if len(nr.Status.NotificationRecordByName) == 0 {
+		return settings, nil
+	}
+
+	defaultStaleTimeout := r.StaleTimeout
+	if defaultStaleTimeout == 0 {
+		defaultStaleTimeout = time.Duration(NotificationRecordStaleTimeoutInHour) * time.Hour
+	}
+	for _, rn := range nr.Status.NotificationRecordByName {
+		settings[rn.NotificationName] = notificationSettings{staleTimeout: defaultStaleTimeout}
+	}
+
+	fleetNotifications := ocmagentv1alpha1.ManagedFleetNotificationList{}
+	if err := r.List(ctx, &fleetNotifications, client.InNamespace(nr.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, fn := range fleetNotifications.Items {
+		n := fn.Spec.FleetNotification
+		s, ok := settings[n.Name]
+		if !ok {
+			continue
+		}
+		s.notification = n.DeepCopy()
+		if n.StaleTimeout != nil {
+			s.staleTimeout = time.Duration(*n.StaleTimeout) * time.Hour
+		}
+		settings[n.Name] = s
+	}
+	return settings, nil
+}
+
+// findSettingsDrift returns the notification records whose resend wait or send limit differs from the
+// one of their ManagedFleetNotification. Records of notifications no longer defined are left as they are.
+func findSettingsDrift(nr *ocmagentv1alpha1.ManagedFleetNotificationRecord, settings map[string]notificationSettings) []settingsDrift {
+	var drifted []settingsDrift
 	for n, rn := range nr.Status.NotificationRecordByName {
-		resendWait := rn.ResendWait
+		notification := settings[rn.NotificationName].notification
+		if notification != nil && !rn.HasFleetNotificationSettings(*notification) {
+			drifted = append(drifted, settingsDrift{nameSlot: n, current: rn, notification: *notification})
+		}
+	}
+	return drifted
+}
+
+// findStaleRecordItems returns the items of the record that have no last transition time or have not
+// been updated within their resend wait and stale timeout, in the order they appear in the record.
+// Items of a notification with a send limit are kept for at least its window, so their recent sends
+// still count against the limit.
+func findStaleRecordItems(nr *ocmagentv1alpha1.ManagedFleetNotificationRecord, now time.Time, settings map[string]notificationSettings) []staleRecordItem {
+	var staleItems []staleRecordItem
+	for n, rn := range nr.Status.NotificationRecordByName {
+		keepFor := time.Duration(rn.ResendWait)*time.Hour + settings[rn.NotificationName].staleTimeout
+		if rn.MaxSendsPerWindow != nil && rn.Window != nil {
+			keepFor = max(keepFor, time.Duration(*rn.Window)*time.Hour)
+		}
 		for i, ri := range rn.NotificationRecordItems {
+			item := staleRecordItem{
+				nameSlot:         n,
+				itemSlot:         i,
+				notificationName: rn.NotificationName,
+				hostedClusterID:  ri.HostedClusterID,
+			}
+
 			// If the LastTransitionTime is nil, consider the item as stale
 			if ri.LastTransitionTime == nil {
 				log.Info(fmt.Sprintf("NotificationRecord for notification %s and hostedcluster %s has an empty lastTransitionTime, cleaning up... ",
 					rn.NotificationName, ri.HostedClusterID))
-				err := r.patchRemove(ctx, &nr, n, i)
-				if err != nil {
-					return ctrl.Result{}, err
-				}
-				return ctrl.Result{}, nil
+				item.reason = localmetrics.RecordItemRemovalReasonNoLastTransitionTime
+				staleItems = append(staleItems, item)
+				continue
 			}
 
-			// Consider the record is stale if the lastSendTime is older than resendWait + 15 days
-			eol := ri.LastTransitionTime.Add(time.Duration(resendWait+NotificationRecordStaleTimeoutInHour) * time.Hour)
-			if time.Now().After(eol) {
+			// Consider the record is stale if the lastSendTime is older than resendWait + the stale timeout,
+			// or the send window when it is longer
+			eol := ri.LastTransitionTime.Add(keepFor)
+			if now.After(eol) {
 				log.Info(fmt.Sprintf("NotificationRecord for notification %s and hostedcluster %s has not been updated "+
-					"for %d hours and considered as stale, cleaning up...", rn.NotificationName, ri.HostedClusterID,
-					resendWait+NotificationRecordStaleTimeoutInHour))
-				err := r.patchRemove(ctx, &nr, n, i)
-				if err != nil {
-					return ctrl.Result{}, err
-				}
-				return ctrl.Result{}, nil
+					"for %s and considered as stale, cleaning up...", rn.NotificationName, ri.HostedClusterID, keepFor))
+				item.reason = localmetrics.RecordItemRemovalReasonStale
+				staleItems = append(staleItems, item)
+			}
+		}
+	}
+	return staleItems
+}
+
+// updateRecordMetrics reports the number of items for each notification of the record and the
+// time since its least recently updated item was updated
+func updateRecordMetrics(nr *ocmagentv1alpha1.ManagedFleetNotificationRecord, now time.Time) {
+	mc := nr.Status.ManagementCluster
+	// The other shards of the management cluster report their own notifications
+	if !nr.IsNotificationRecordShard() {
+		localmetrics.ResetMetricFleetNotificationRecordItems(mc)
+	}
+	for _, rn := range nr.Status.NotificationRecordByName {
+		localmetrics.UpdateMetricFleetNotificationRecordItems(mc, rn.NotificationName, len(rn.NotificationRecordItems))
+		var oldest *metav1.Time
+		for _, ri := range rn.NotificationRecordItems {
+			if ri.LastTransitionTime != nil && (oldest == nil || ri.LastTransitionTime.Before(oldest)) {
+				oldest = ri.LastTransitionTime
 			}
 		}
+		if oldest == nil {
+			localmetrics.ResetMetricFleetNotificationRecordOldestItemAge(mc, rn.NotificationName)
+			continue
+		}
+		localmetrics.UpdateMetricFleetNotificationRecordOldestItemAge(mc, rn.NotificationName, now.Sub(oldest.Time))
 	}
-	return ctrl.Result{}, nil
 }
 
-func (r *ManagedFleetNotificationReconciler) patchRemove(ctx context.Context, notificationRecord *ocmagentv1alpha1.ManagedFleetNotificationRecord,
-	notificationRecordByNameSlot int, notificationRecordItemSlot int) error {
-	patch := []byte(fmt.Sprintf(`[{"op": "remove", "path": "/status/notificationRecordByName/%d/notificationRecordItems/%d"}]`,
-		notificationRecordByNameSlot, notificationRecordItemSlot))
+// jsonPatchOperation is a single operation of a JSON patch
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
 
-	err := r.Client.Status().Patch(ctx, notificationRecord, client.RawPatch(types.JSONPatchType, patch))
+// patchRemoveItems removes the supplied items from the record with a single JSON patch. Each removal
+// is preceded by test operations on the notification name and hosted cluster ID at its position, so
+// the patch is rejected rather than removing the wrong item when the record was changed meanwhile.
+func (r *ManagedFleetNotificationReconciler) patchRemoveItems(ctx context.Context, notificationRecord *ocmagentv1alpha1.ManagedFleetNotificationRecord,
+	items []staleRecordItem) error {
+	ops := make([]jsonPatchOperation, 0, 3*len(items))
+	// Remove the items last to first, so that each removal leaves the positions of the remaining items unchanged
+	for i := len(items) - 1; i >= 0; i-- {
+		namePath := fmt.Sprintf("/status/notificationRecordByName/%d", items[i].nameSlot)
+		itemPath := fmt.Sprintf("%s/notificationRecordItems/%d", namePath, items[i].itemSlot)
+		ops = append(ops,
+			jsonPatchOperation{Op: "test", Path: namePath + "/notificationName", Value: items[i].notificationName},
+			jsonPatchOperation{Op: "test", Path: itemPath + "/hostedClusterID", Value: items[i].hostedClusterID},
+			jsonPatchOperation{Op: "remove", Path: itemPath},
+		)
+	}
+	patch, err := json.Marshal(ops)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.Client.Status().Patch(ctx, notificationRecord, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// patchRecordSettings sets the resend wait and send limit of the supplied notification records with a
+// single JSON patch. The changes of each notification are preceded by a test operation on the notification
+// name at its position, so the patch is rejected rather than changing the wrong notification when the
+// record was changed meanwhile.
+func (r *ManagedFleetNotificationReconciler) patchRecordSettings(ctx context.Context, notificationRecord *ocmagentv1alpha1.ManagedFleetNotificationRecord,
+	drifted []settingsDrift) error {
+	ops := make([]jsonPatchOperation, 0, 4*len(drifted))
+	for _, drift := range drifted {
+		namePath := fmt.Sprintf("/status/notificationRecordByName/%d", drift.nameSlot)
+		ops = append(ops,
+			jsonPatchOperation{Op: "test", Path: namePath + "/notificationName", Value: drift.current.NotificationName},
+			jsonPatchOperation{Op: "replace", Path: namePath + "/resendWait", Value: drift.notification.ResendWait},
+		)
+		ops = appendOptionalFieldPatch(ops, namePath+"/maxSendsPerWindow", drift.current.MaxSendsPerWindow, drift.notification.MaxSendsPerWindow)
+		ops = appendOptionalFieldPatch(ops, namePath+"/window", drift.current.Window, drift.notification.Window)
+	}
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+
+	return r.Client.Status().Patch(ctx, notificationRecord, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// appendOptionalFieldPatch appends the operation setting an optional field to the desired value, or
+// removing it when the desired value is unset
+func appendOptionalFieldPatch(ops []jsonPatchOperation, path string, current, desired *int32) []jsonPatchOperation {
+	switch {
+	case desired != nil:
+		// add replaces the field when it is already set
+		return append(ops, jsonPatchOperation{Op: "add", Path: path, Value: *desired})
+	case current != nil:
+		return append(ops, jsonPatchOperation{Op: "remove", Path: path})
+	default:
+		return ops
+	}
+}
+
+// enqueueRecordsForFleetNotification maps a change to a ManagedFleetNotification to a reconcile of the
+// records of its notification whose settings no longer match
+func (r *ManagedFleetNotificationReconciler) enqueueRecordsForFleetNotification(ctx context.Context, obj client.Object) []reconcile.Request {
+	fn, ok := obj.(*ocmagentv1alpha1.ManagedFleetNotification)
+	if !ok {
+		return nil
+	}
+
+	records := &ocmagentv1alpha1.ManagedFleetNotificationRecordList{}
+	if err := r.List(ctx, records, client.InNamespace(fn.Namespace)); err != nil {
+		log.Error(err, "Failed to list ManagedFleetNotificationRecords to reconcile")
+		return nil
+	}
+
+	n := fn.Spec.FleetNotification
+	var requests []reconcile.Request
+	for _, record := range records.Items {
+		for _, rn := range record.Status.NotificationRecordByName {
+			if rn.NotificationName == n.Name && !rn.HasFleetNotificationSettings(n) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: record.Namespace, Name: record.Name},
+				})
+				break
+			}
+		}
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// The ManagedFleetNotifications are watched as well, so that a change of their resend wait or send
+// limit is propagated to the records.
 func (r *ManagedFleetNotificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		// Uncomment the following line adding a pointer to an instance of the controlled resource as an argument
 		For(&ocmagentv1alpha1.ManagedFleetNotificationRecord{}).
+		Watches(&ocmagentv1alpha1.ManagedFleetNotification{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRecordsForFleetNotification),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		WithEventFilter(eventPredicates()).
 		Complete(r)
 }
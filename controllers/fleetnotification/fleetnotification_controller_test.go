@@ -1,11 +1,19 @@
 package fleetnotification_test
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	k8serrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
@@ -14,9 +22,15 @@ import (
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	"github.com/openshift/ocm-agent-operator/controllers/fleetnotification"
+	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
 	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
 )
 
+const (
+	testManagementCluster = "test-mc"
+	testNotificationName  = "test-notification"
+)
+
 var _ = Describe("FleetNotification Controller", func() {
 	var (
 		mockClient                  *clientmocks.MockClient
@@ -24,15 +38,20 @@ var _ = Describe("FleetNotification Controller", func() {
 		mockCtrl                    *gomock.Controller
 		fleetNotificationReconciler *fleetnotification.ManagedFleetNotificationReconciler
 		testFleetNotificationRecord *ocmagentv1alpha1.ManagedFleetNotificationRecord
+		fakeClock                   *testing.FakeClock
+		now                         time.Time
 	)
 
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockClient = clientmocks.NewMockClient(mockCtrl)
 		mockStatusWriter = clientmocks.NewMockStatusWriter(mockCtrl)
+		now = time.Date(2026, time.October, 14, 12, 30, 0, 0, time.UTC)
+		fakeClock = testing.NewFakeClock(now)
 		fleetNotificationReconciler = &fleetnotification.ManagedFleetNotificationReconciler{
 			Client: mockClient,
 			Scheme: testconst.Scheme,
+			Clock:  fakeClock,
 		}
 	})
 
@@ -47,6 +66,14 @@ var _ = Describe("FleetNotification Controller", func() {
 			}
 		})
 
+		// The stale items are cleaned up the same way from the record shards and the unsharded records
+		JustBeforeEach(func() {
+			testFleetNotificationRecord.Labels = map[string]string{
+				ocmagentv1alpha1.ManagementClusterLabel:    testManagementCluster,
+				ocmagentv1alpha1.NotificationNameHashLabel: "test",
+			}
+		})
+
 		When("There is no notificationrecord items", func() {
 			It("Won't need to do the garbage collection", func() {
 				gomock.InOrder(
@@ -66,13 +93,15 @@ var _ = Describe("FleetNotification Controller", func() {
 						Namespace: testconst.MfnrNamespacedName.Namespace,
 					},
 					Status: ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+						ManagementCluster: testManagementCluster,
 						NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
 							{
+								NotificationName: testNotificationName,
 								NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
 									{
 										HostedClusterID:             "1234-5678-12345678",
 										FiringNotificationSentCount: 1,
-										LastTransitionTime:          &metav1.Time{Time: time.Now()},
+										LastTransitionTime:          &metav1.Time{Time: now},
 									},
 								},
 							},
@@ -83,11 +112,43 @@ var _ = Describe("FleetNotification Controller", func() {
 			It("Won't need to do the garbage collection", func() {
 				gomock.InOrder(
 					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
 				)
 				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
 				Expect(err).To(BeNil())
 				Expect(err).NotTo(HaveOccurred())
 			})
+			It("Reports the number of record items", func() {
+				localmetrics.MetricFleetNotificationRecordOldestItemAge.Reset()
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(testutil.ToFloat64(localmetrics.MetricFleetNotificationRecordItems.WithLabelValues(
+					testManagementCluster, testNotificationName))).To(Equal(float64(1)))
+				Expect(testutil.ToFloat64(localmetrics.MetricFleetNotificationRecordOldestItemAge.WithLabelValues(
+					testManagementCluster, testNotificationName))).To(Equal(float64(0)))
+			})
+			It("Reports the age of the least recently updated item", func() {
+				localmetrics.MetricFleetNotificationRecordOldestItemAge.Reset()
+				rn := &testFleetNotificationRecord.Status.NotificationRecordByName[0]
+				rn.NotificationRecordItems = append(rn.NotificationRecordItems, ocmagentv1alpha1.NotificationRecordItem{
+					HostedClusterID:             "8765-4321-87654321",
+					FiringNotificationSentCount: 1,
+					LastTransitionTime:          &metav1.Time{Time: now.Add(-30 * time.Minute)},
+				})
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(testutil.ToFloat64(localmetrics.MetricFleetNotificationRecordOldestItemAge.WithLabelValues(
+					testManagementCluster, testNotificationName))).To(Equal((30 * time.Minute).Seconds()))
+				Expect(testutil.CollectAndCount(localmetrics.MetricFleetNotificationRecordOldestItemAge)).To(Equal(1))
+			})
 		})
 
 		When("There is notification record which was sent before and stale", func() {
@@ -98,8 +159,10 @@ var _ = Describe("FleetNotification Controller", func() {
 						Namespace: testconst.MfnrNamespacedName.Namespace,
 					},
 					Status: ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+						ManagementCluster: testManagementCluster,
 						NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
 							{
+								NotificationName: testNotificationName,
 								NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
 									{
 										HostedClusterID:             "1234-5678-12345678",
@@ -113,14 +176,19 @@ var _ = Describe("FleetNotification Controller", func() {
 				}
 			})
 			It("Will need to do the garbage collection for it", func() {
+				removed := localmetrics.MetricFleetNotificationRecordItemsRemoved.WithLabelValues(
+					testManagementCluster, testNotificationName, localmetrics.RecordItemRemovalReasonStale)
+				removedBefore := testutil.ToFloat64(removed)
 				gomock.InOrder(
 					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
 					mockClient.EXPECT().Status().Return(mockStatusWriter),
 					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, *testFleetNotificationRecord),
 				)
 				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
 				Expect(err).To(BeNil())
 				Expect(err).NotTo(HaveOccurred())
+				Expect(testutil.ToFloat64(removed)).To(Equal(removedBefore + 1))
 			})
 		})
 
@@ -132,8 +200,10 @@ var _ = Describe("FleetNotification Controller", func() {
 						Namespace: testconst.MfnrNamespacedName.Namespace,
 					},
 					Status: ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+						ManagementCluster: testManagementCluster,
 						NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
 							{
+								NotificationName: testNotificationName,
 								NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
 									{
 										HostedClusterID:             "1234-5678-12345678",
@@ -147,15 +217,532 @@ var _ = Describe("FleetNotification Controller", func() {
 				}
 			})
 			It("Will need to do the garbage collection for it", func() {
+				removed := localmetrics.MetricFleetNotificationRecordItemsRemoved.WithLabelValues(
+					testManagementCluster, testNotificationName, localmetrics.RecordItemRemovalReasonNoLastTransitionTime)
+				removedBefore := testutil.ToFloat64(removed)
 				gomock.InOrder(
 					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
 					mockClient.EXPECT().Status().Return(mockStatusWriter),
 					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, *testFleetNotificationRecord),
 				)
 				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
 				Expect(err).To(BeNil())
 				Expect(err).NotTo(HaveOccurred())
+				Expect(testutil.ToFloat64(removed)).To(Equal(removedBefore + 1))
+			})
+		})
+
+		When("There are stale items for several notifications", func() {
+			var staleTime, recentTime *metav1.Time
+			BeforeEach(func() {
+				staleTime = &metav1.Time{Time: now.Add(-time.Duration(fleetnotification.NotificationRecordStaleTimeoutInHour+1) * time.Hour)}
+				recentTime = &metav1.Time{Time: now}
+				testFleetNotificationRecord.Status = ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+					ManagementCluster: testManagementCluster,
+					NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+						{
+							NotificationName: "notification-a",
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-1", LastTransitionTime: staleTime},
+								{HostedClusterID: "hc-2", LastTransitionTime: recentTime},
+								{HostedClusterID: "hc-3", LastTransitionTime: nil},
+							},
+						},
+						{
+							NotificationName: "notification-b",
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-4", LastTransitionTime: staleTime},
+							},
+						},
+					},
+				}
+			})
+			It("Removes all of them with a single guarded patch and checks the record again later", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+							Expect(patch.Type()).To(Equal(types.JSONPatchType))
+							data, err := patch.Data(obj)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(data).To(MatchJSON(`[
+								{"op": "test", "path": "/status/notificationRecordByName/1/notificationName", "value": "notification-b"},
+								{"op": "test", "path": "/status/notificationRecordByName/1/notificationRecordItems/0/hostedClusterID", "value": "hc-4"},
+								{"op": "remove", "path": "/status/notificationRecordByName/1/notificationRecordItems/0"},
+								{"op": "test", "path": "/status/notificationRecordByName/0/notificationName", "value": "notification-a"},
+								{"op": "test", "path": "/status/notificationRecordByName/0/notificationRecordItems/2/hostedClusterID", "value": "hc-3"},
+								{"op": "remove", "path": "/status/notificationRecordByName/0/notificationRecordItems/2"},
+								{"op": "test", "path": "/status/notificationRecordByName/0/notificationName", "value": "notification-a"},
+								{"op": "test", "path": "/status/notificationRecordByName/0/notificationRecordItems/0/hostedClusterID", "value": "hc-1"},
+								{"op": "remove", "path": "/status/notificationRecordByName/0/notificationRecordItems/0"}
+							]`))
+							return nil
+						}),
+				)
+				result, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(fleetnotification.NotificationRecordCleanupInterval))
+			})
+			It("Retries when the record changed before the patch was applied", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(
+						k8serrs.NewInvalid(schema.GroupKind{}, testFleetNotificationRecord.Name, nil)),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("The stale timeout is configured", func() {
+			BeforeEach(func() {
+				lastTransitionTime := &metav1.Time{Time: now.Add(-3 * time.Hour)}
+				testFleetNotificationRecord.Status = ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+					ManagementCluster: testManagementCluster,
+					NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+						{
+							NotificationName: "noisy-notification",
+							ResendWait:       1,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-1", LastTransitionTime: lastTransitionTime},
+							},
+						},
+						{
+							NotificationName: "other-notification",
+							ResendWait:       1,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-2", LastTransitionTime: lastTransitionTime},
+							},
+						},
+					},
+				}
+			})
+			It("Uses the stale timeout of the ManagedFleetNotification over the default", func() {
+				staleTimeout := int32(1)
+				fleetNotifications := ocmagentv1alpha1.ManagedFleetNotificationList{
+					Items: []ocmagentv1alpha1.ManagedFleetNotification{
+						{Spec: ocmagentv1alpha1.ManagedFleetNotificationSpec{FleetNotification: ocmagentv1alpha1.FleetNotification{
+							Name: "noisy-notification", ResendWait: 1, StaleTimeout: &staleTimeout,
+						}}},
+					},
+				}
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace(testconst.MfnrNamespacedName.Namespace)).Times(1).SetArg(1, fleetNotifications),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+							data, err := patch.Data(obj)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(data)).To(ContainSubstring(`"value":"hc-1"`))
+							Expect(string(data)).NotTo(ContainSubstring(`"value":"hc-2"`))
+							return nil
+						}),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("Uses the stale timeout of the reconciler when the ManagedFleetNotification sets none", func() {
+				fleetNotificationReconciler.StaleTimeout = time.Hour
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+							data, err := patch.Data(obj)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(data)).To(ContainSubstring(`"value":"hc-1"`))
+							Expect(string(data)).To(ContainSubstring(`"value":"hc-2"`))
+							return nil
+						}),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("An item reaches the end of its resend wait and stale timeout", func() {
+			BeforeEach(func() {
+				fleetNotificationReconciler.StaleTimeout = time.Hour
+				testFleetNotificationRecord.Status = ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+					ManagementCluster: testManagementCluster,
+					NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+						{
+							NotificationName: testNotificationName,
+							ResendWait:       1,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-1", LastTransitionTime: &metav1.Time{Time: now.Add(-2 * time.Hour)}},
+							},
+						},
+					},
+				}
+			})
+			It("Keeps the item exactly at the end of the stale timeout", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("Removes the item just after the end of the stale timeout", func() {
+				fakeClock.Step(time.Nanosecond)
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("An item of a notification with a send limit outlives its resend wait and stale timeout", func() {
+			BeforeEach(func() {
+				maxSends, window := int32(2), int32(24)
+				fleetNotificationReconciler.StaleTimeout = time.Hour
+				testFleetNotificationRecord.Status = ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+					ManagementCluster: testManagementCluster,
+					NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+						{
+							NotificationName:  testNotificationName,
+							ResendWait:        1,
+							MaxSendsPerWindow: &maxSends,
+							Window:            &window,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-1", LastTransitionTime: &metav1.Time{Time: now.Add(-24 * time.Hour)}},
+							},
+						},
+					},
+				}
+			})
+			It("Keeps the item until the end of the send window", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("Removes the item just after the end of the send window", func() {
+				fakeClock.Step(time.Nanosecond)
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("The resend wait of a ManagedFleetNotification was changed", func() {
+			var fleetNotifications ocmagentv1alpha1.ManagedFleetNotificationList
+			BeforeEach(func() {
+				lastTransitionTime := &metav1.Time{Time: now}
+				testFleetNotificationRecord.Status = ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+					ManagementCluster: testManagementCluster,
+					NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+						{
+							NotificationName: "unchanged-notification",
+							ResendWait:       24,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-1", LastTransitionTime: lastTransitionTime},
+							},
+						},
+						{
+							NotificationName: testNotificationName,
+							ResendWait:       24,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-2", LastTransitionTime: &metav1.Time{Time: now.Add(-3 * time.Hour)}},
+							},
+						},
+					},
+				}
+				fleetNotifications = ocmagentv1alpha1.ManagedFleetNotificationList{
+					Items: []ocmagentv1alpha1.ManagedFleetNotification{
+						{Spec: ocmagentv1alpha1.ManagedFleetNotificationSpec{FleetNotification: ocmagentv1alpha1.FleetNotification{
+							Name: "unchanged-notification", ResendWait: 24,
+						}}},
+						{Spec: ocmagentv1alpha1.ManagedFleetNotificationSpec{FleetNotification: ocmagentv1alpha1.FleetNotification{
+							Name: testNotificationName, ResendWait: 1,
+						}}},
+					},
+				}
+			})
+			It("Propagates the new resend wait to the record", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, fleetNotifications),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+							Expect(patch.Type()).To(Equal(types.JSONPatchType))
+							data, err := patch.Data(obj)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(data).To(MatchJSON(fmt.Sprintf(`[
+								{"op":"test","path":"/status/notificationRecordByName/1/notificationName","value":%q},
+								{"op":"replace","path":"/status/notificationRecordByName/1/resendWait","value":1}
+							]`, testNotificationName)))
+							return nil
+						}),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("Propagates the send limit to the record", func() {
+				maxSends, window, oldWindow := int32(3), int32(12), int32(48)
+				testFleetNotificationRecord.Status.NotificationRecordByName[0].Window = &oldWindow
+				fleetNotifications.Items[1].Spec.FleetNotification.ResendWait = 24
+				fleetNotifications.Items[1].Spec.FleetNotification.MaxSendsPerWindow = &maxSends
+				fleetNotifications.Items[1].Spec.FleetNotification.Window = &window
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, fleetNotifications),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+							data, err := patch.Data(obj)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(data).To(MatchJSON(fmt.Sprintf(`[
+								{"op":"test","path":"/status/notificationRecordByName/0/notificationName","value":"unchanged-notification"},
+								{"op":"replace","path":"/status/notificationRecordByName/0/resendWait","value":24},
+								{"op":"remove","path":"/status/notificationRecordByName/0/window"},
+								{"op":"test","path":"/status/notificationRecordByName/1/notificationName","value":%q},
+								{"op":"replace","path":"/status/notificationRecordByName/1/resendWait","value":24},
+								{"op":"add","path":"/status/notificationRecordByName/1/maxSendsPerWindow","value":3},
+								{"op":"add","path":"/status/notificationRecordByName/1/window","value":12}
+							]`, testNotificationName)))
+							return nil
+						}),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("Uses the new resend wait to find the stale items", func() {
+				fleetNotificationReconciler.StaleTimeout = time.Hour
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, fleetNotifications),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+							data, err := patch.Data(obj)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(data)).To(ContainSubstring(`"value":"hc-2"`))
+							Expect(string(data)).NotTo(ContainSubstring(`"value":"hc-1"`))
+							return nil
+						}),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("Returns the error when the record cannot be patched", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+					mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(1, fleetNotifications),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(
+						k8serrs.NewInvalid(schema.GroupKind{}, testFleetNotificationRecord.Name, nil)),
+				)
+				_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).To(HaveOccurred())
+			})
+			It("Enqueues the records whose resend wait differs", func() {
+				other := testFleetNotificationRecord.DeepCopy()
+				other.Name = "in-line"
+				other.Status.NotificationRecordByName[1].ResendWait = 1
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace(testconst.MfnrNamespacedName.Namespace)).SetArg(1,
+					ocmagentv1alpha1.ManagedFleetNotificationRecordList{Items: []ocmagentv1alpha1.ManagedFleetNotificationRecord{*testFleetNotificationRecord, *other}})
+				fn := fleetNotifications.Items[1].DeepCopy()
+				fn.Namespace = testconst.MfnrNamespacedName.Namespace
+				requests := fleetnotification.EnqueueRecordsForFleetNotification(fleetNotificationReconciler, testconst.Context, fn)
+				Expect(requests).To(Equal([]reconcile.Request{{NamespacedName: testconst.MfnrNamespacedName}}))
+			})
+		})
+
+		When("The record no longer exists", func() {
+			It("Does nothing", func() {
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).Return(
+					k8serrs.NewNotFound(schema.GroupResource{}, testFleetNotificationRecord.Name))
+				result, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeZero())
 			})
 		})
 	})
+
+	Context("Migrating an unsharded ManagedFleetNotificationRecord", func() {
+		var existingShard *ocmagentv1alpha1.ManagedFleetNotificationRecord
+		BeforeEach(func() {
+			fleetNotificationReconciler.ShardRecords = true
+			lastTransitionTime := &metav1.Time{Time: now}
+			testFleetNotificationRecord = &ocmagentv1alpha1.ManagedFleetNotificationRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            testconst.MfnrNamespacedName.Name,
+					Namespace:       testconst.MfnrNamespacedName.Namespace,
+					ResourceVersion: "5",
+				},
+				Status: ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+					ManagementCluster: testManagementCluster,
+					NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+						{
+							NotificationName: "notification-a",
+							ResendWait:       1,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-1", LastTransitionTime: lastTransitionTime},
+							},
+						},
+						{
+							NotificationName: "notification-b",
+							ResendWait:       1,
+							NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+								{HostedClusterID: "hc-2", LastTransitionTime: lastTransitionTime},
+								{HostedClusterID: "hc-3", LastTransitionTime: lastTransitionTime},
+							},
+						},
+					},
+				},
+			}
+			existingShard = ocmagentv1alpha1.NewNotificationRecordShard(testconst.MfnrNamespacedName.Namespace, testManagementCluster,
+				ocmagentv1alpha1.NotificationRecordByName{
+					NotificationName: "notification-b",
+					ResendWait:       1,
+					NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+						{HostedClusterID: "hc-2", FiringNotificationSentCount: 5, LastTransitionTime: lastTransitionTime},
+					},
+				})
+		})
+
+		It("Moves the records of each notification to its shard and deletes the unsharded record", func() {
+			shardA := types.NamespacedName{
+				Namespace: testconst.MfnrNamespacedName.Namespace,
+				Name:      ocmagentv1alpha1.NotificationRecordShardName(testManagementCluster, "notification-a"),
+			}
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+				// notification-a has no shard yet
+				mockClient.EXPECT().Get(gomock.Any(), shardA, gomock.Any()).Times(1).Return(
+					k8serrs.NewNotFound(schema.GroupResource{}, shardA.Name)),
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, o *ocmagentv1alpha1.ManagedFleetNotificationRecord, opts ...client.CreateOption) error {
+						Expect(o.Name).To(Equal(shardA.Name))
+						Expect(o.IsNotificationRecordShard()).To(BeTrue())
+						o.Status = ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{}
+						return nil
+					}),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, o *ocmagentv1alpha1.ManagedFleetNotificationRecord, opts ...client.SubResourceUpdateOption) error {
+						Expect(o.Status.ManagementCluster).To(Equal(testManagementCluster))
+						Expect(o.Status.NotificationRecordByName).To(HaveLen(1))
+						Expect(o.Status.NotificationRecordByName[0].NotificationRecordItems).To(HaveLen(1))
+						return nil
+					}),
+				// notification-b already has a shard, which only gets the missing items
+				mockClient.EXPECT().Get(gomock.Any(), client.ObjectKeyFromObject(existingShard), gomock.Any()).Times(1).SetArg(2, *existingShard),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, o *ocmagentv1alpha1.ManagedFleetNotificationRecord, opts ...client.SubResourceUpdateOption) error {
+						items := o.Status.NotificationRecordByName[0].NotificationRecordItems
+						Expect(items).To(HaveLen(2))
+						Expect(items[0].HostedClusterID).To(Equal("hc-2"))
+						Expect(items[0].FiringNotificationSentCount).To(Equal(5))
+						Expect(items[1].HostedClusterID).To(Equal("hc-3"))
+						return nil
+					}),
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, o *ocmagentv1alpha1.ManagedFleetNotificationRecord, opts ...client.DeleteOption) error {
+						Expect(o.Name).To(Equal(testconst.MfnrNamespacedName.Name))
+						deleteOpts := &client.DeleteOptions{}
+						deleteOpts.ApplyOptions(opts)
+						Expect(*deleteOpts.Preconditions.ResourceVersion).To(Equal("5"))
+						return nil
+					}),
+			)
+			_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Moves the records again when the unsharded record was updated before it is deleted", func() {
+			testFleetNotificationRecord.Status.NotificationRecordByName = testFleetNotificationRecord.Status.NotificationRecordByName[:1]
+			shardA := ocmagentv1alpha1.NewNotificationRecordShard(testconst.MfnrNamespacedName.Namespace, testManagementCluster,
+				testFleetNotificationRecord.Status.NotificationRecordByName[0])
+			updatedRecord := testFleetNotificationRecord.DeepCopy()
+			updatedRecord.ResourceVersion = "6"
+			updatedRecord.Status.NotificationRecordByName[0].NotificationRecordItems = append(
+				updatedRecord.Status.NotificationRecordByName[0].NotificationRecordItems,
+				ocmagentv1alpha1.NotificationRecordItem{HostedClusterID: "hc-4", LastTransitionTime: &metav1.Time{Time: now}})
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+				mockClient.EXPECT().Get(gomock.Any(), client.ObjectKeyFromObject(shardA), gomock.Any()).Times(1).SetArg(2, *shardA),
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(
+					k8serrs.NewConflict(schema.GroupResource{}, testconst.MfnrNamespacedName.Name, fmt.Errorf("conflict"))),
+				// the OCM Agent added an item to the unsharded record, which is moved on the retry
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *updatedRecord),
+				mockClient.EXPECT().Get(gomock.Any(), client.ObjectKeyFromObject(shardA), gomock.Any()).Times(1).SetArg(2, *shardA),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, o *ocmagentv1alpha1.ManagedFleetNotificationRecord, opts ...client.SubResourceUpdateOption) error {
+						items := o.Status.NotificationRecordByName[0].NotificationRecordItems
+						Expect(items).To(HaveLen(2))
+						Expect(items[1].HostedClusterID).To(Equal("hc-4"))
+						return nil
+					}),
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, o *ocmagentv1alpha1.ManagedFleetNotificationRecord, opts ...client.DeleteOption) error {
+						deleteOpts := &client.DeleteOptions{}
+						deleteOpts.ApplyOptions(opts)
+						Expect(*deleteOpts.Preconditions.ResourceVersion).To(Equal("6"))
+						return nil
+					}),
+			)
+			_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Keeps the unsharded record and cleans it up in place when sharding is disabled", func() {
+			fleetNotificationReconciler.ShardRecords = false
+			testFleetNotificationRecord.Status.NotificationRecordByName[0].NotificationRecordItems[0].LastTransitionTime = nil
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Times(1),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+						Expect(obj.GetName()).To(Equal(testconst.MfnrNamespacedName.Name))
+						data, err := patch.Data(obj)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(data)).To(ContainSubstring(`"value":"hc-1"`))
+						return nil
+					}),
+			)
+			mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+			mockClient.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Keeps the unsharded record when a shard cannot be written", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnrNamespacedName, gomock.Any()).Times(1).SetArg(2, *testFleetNotificationRecord),
+				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(
+					k8serrs.NewNotFound(schema.GroupResource{}, "shard")),
+				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).Return(fmt.Errorf("fake error")),
+			)
+			mockClient.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			_, err := fleetNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnrNamespacedName})
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
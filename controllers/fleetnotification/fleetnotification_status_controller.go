@@ -0,0 +1,200 @@
+package fleetnotification
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	ctrlconst "github.com/openshift/ocm-agent-operator/pkg/consts/controller"
+	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
+)
+
+// ManagedFleetNotificationStatusReconciler summarises the records of a ManagedFleetNotification
+// across all the ManagedFleetNotificationRecords into its status, and removes them once the
+// ManagedFleetNotification is deleted
+type ManagedFleetNotificationStatusReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+var _ reconcile.Reconciler = &ManagedFleetNotificationStatusReconciler{}
+
+// Reconcile updates the status of a ManagedFleetNotification with the number of hosted clusters
+// that have a record of the notification, the notifications sent and the last time one was sent.
+// A finalizer keeps a deleted ManagedFleetNotification until the records of its notification are removed.
+func (r *ManagedFleetNotificationStatusReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling FleetNotification status")
+
+	fn := ocmagentv1alpha1.ManagedFleetNotification{}
+	err := r.Get(ctx, request.NamespacedName, &fn)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Is the ManagedFleetNotification being deleted?
+	if !fn.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(&fn, ctrlconst.ReconcileFleetNotificationFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		if err := r.removeNotificationRecords(ctx, &fn); err != nil {
+			reqLogger.Error(err, "Failed to remove the records of the deleted notification. Will retry on next reconcile.")
+			return reconcile.Result{}, err
+		}
+		// The finalizer can now be removed
+		patchBase := finalizerPatchBase(&fn)
+		controllerutil.RemoveFinalizer(&fn, ctrlconst.ReconcileFleetNotificationFinalizer)
+		if err := r.Patch(ctx, &fn, patchBase); err != nil {
+			reqLogger.Error(err, "Failed to remove finalizer from ManagedFleetNotification resource. Will retry on next reconcile.")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(&fn, ctrlconst.ReconcileFleetNotificationFinalizer) {
+		patchBase := finalizerPatchBase(&fn)
+		controllerutil.AddFinalizer(&fn, ctrlconst.ReconcileFleetNotificationFinalizer)
+		if err := r.Patch(ctx, &fn, patchBase); err != nil {
+			reqLogger.Error(err, "Failed to apply finalizer to ManagedFleetNotification resource. Will retry on next reconcile.")
+			return reconcile.Result{}, err
+		}
+	}
+
+	records := ocmagentv1alpha1.ManagedFleetNotificationRecordList{}
+	if err := r.List(ctx, &records, client.InNamespace(fn.Namespace)); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	status := fn.SummarizeNotificationRecords(records.Items)
+	if equality.Semantic.DeepEqual(fn.Status, status) {
+		return reconcile.Result{}, nil
+	}
+
+	fn.Status = status
+	if err := r.Status().Update(ctx, &fn); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// finalizerPatchBase returns the base of a merge patch changing the finalizers of a ManagedFleetNotification.
+// Only the finalizers are sent rather than the whole object, and the patch is rejected when the finalizers
+// were changed since the ManagedFleetNotification was read, as the merge patch replaces the whole list.
+func finalizerPatchBase(fn *ocmagentv1alpha1.ManagedFleetNotification) client.Patch {
+	return client.MergeFromWithOptions(fn.DeepCopy(), client.MergeFromWithOptimisticLock{})
+}
+
+// removeNotificationRecords removes the records of the notification of a deleted ManagedFleetNotification
+// from every ManagedFleetNotificationRecord. The record shard of the notification is deleted, and the
+// notification is removed from the records that have not been migrated to shards yet. The records are
+// kept when another ManagedFleetNotification still defines a notification of the same name.
+func (r *ManagedFleetNotificationStatusReconciler) removeNotificationRecords(ctx context.Context, fn *ocmagentv1alpha1.ManagedFleetNotification) error {
+	name := fn.Spec.FleetNotification.Name
+
+	fleetNotifications := ocmagentv1alpha1.ManagedFleetNotificationList{}
+	if err := r.List(ctx, &fleetNotifications, client.InNamespace(fn.Namespace)); err != nil {
+		return err
+	}
+	for _, other := range fleetNotifications.Items {
+		if other.UID != fn.UID && other.DeletionTimestamp.IsZero() && other.Spec.FleetNotification.Name == name {
+			log.Info("Notification is still defined by another ManagedFleetNotification, keeping its records",
+				"notification", name, "ManagedFleetNotification", other.Name)
+			return nil
+		}
+	}
+
+	records := ocmagentv1alpha1.ManagedFleetNotificationRecordList{}
+	if err := r.List(ctx, &records, client.InNamespace(fn.Namespace)); err != nil {
+		return err
+	}
+	for i := range records.Items {
+		record := &records.Items[i]
+		index := ocmagentv1alpha1.NewNotificationRecordIndex(record)
+		rn, ok := index.NotificationRecordByName(name)
+		if !ok {
+			continue
+		}
+		removed := len(rn.NotificationRecordItems)
+
+		index.RemoveNotificationRecordByName(name)
+		if record.IsNotificationRecordShard() && len(record.Status.NotificationRecordByName) == 0 {
+			if err := client.IgnoreNotFound(r.Delete(ctx, record)); err != nil {
+				return err
+			}
+		} else {
+			// The update is guarded by the resourceVersion, so a record changed meanwhile is retried
+			if err := r.Status().Update(ctx, record); err != nil {
+				return err
+			}
+		}
+
+		mc := record.Status.ManagementCluster
+		localmetrics.ResetMetricFleetNotificationRecordNotificationItems(mc, name)
+		for j := 0; j < removed; j++ {
+			localmetrics.UpdateMetricFleetNotificationRecordItemsRemoved(mc, name, localmetrics.RecordItemRemovalReasonNotificationDeleted)
+		}
+		log.Info("Removed the records of the deleted notification", "notification", name, "record", record.Name, "items", removed)
+	}
+	return nil
+}
+
+// enqueueFleetNotificationsForRecord maps a change to a notification record to a reconcile of
+// the ManagedFleetNotifications of the notifications it holds
+func (r *ManagedFleetNotificationStatusReconciler) enqueueFleetNotificationsForRecord(ctx context.Context, obj client.Object) []reconcile.Request {
+	record, ok := obj.(*ocmagentv1alpha1.ManagedFleetNotificationRecord)
+	if !ok || len(record.Status.NotificationRecordByName) == 0 {
+		return nil
+	}
+
+	fleetNotifications := &ocmagentv1alpha1.ManagedFleetNotificationList{}
+	if err := r.List(ctx, fleetNotifications, client.InNamespace(record.Namespace)); err != nil {
+		log.Error(err, "Failed to list ManagedFleetNotifications to reconcile")
+		return nil
+	}
+
+	names := make(map[string]bool, len(record.Status.NotificationRecordByName))
+	for _, rn := range record.Status.NotificationRecordByName {
+		names[rn.NotificationName] = true
+	}
+
+	var requests []reconcile.Request
+	for _, fn := range fleetNotifications.Items {
+		if names[fn.Spec.FleetNotification.Name] {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: fn.Namespace, Name: fn.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ManagedFleetNotificationStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// The status is written by this controller, only spec changes and deletions are of interest
+		For(&ocmagentv1alpha1.ManagedFleetNotification{}, builder.WithPredicates(predicate.Or[client.Object](
+			predicate.GenerationChangedPredicate{}, predicate.NewPredicateFuncs(isBeingDeleted)))).
+		// Records are deleted when they are migrated to shards, which changes the summary as well
+		Watches(&ocmagentv1alpha1.ManagedFleetNotificationRecord{}, handler.EnqueueRequestsFromMapFunc(r.enqueueFleetNotificationsForRecord)).
+		Complete(r)
+}
+
+// isBeingDeleted reports whether the object has been marked for deletion
+func isBeingDeleted(obj client.Object) bool {
+	return !obj.GetDeletionTimestamp().IsZero()
+}
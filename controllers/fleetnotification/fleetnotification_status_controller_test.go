@@ -0,0 +1,287 @@
+package fleetnotification_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/mock/gomock"
+	k8serrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	"github.com/openshift/ocm-agent-operator/controllers/fleetnotification"
+	ctrlconst "github.com/openshift/ocm-agent-operator/pkg/consts/controller"
+	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
+	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+)
+
+// expectFinalizerPatch expects a merge patch that only changes the finalizers of the object
+func expectFinalizerPatch(obj client.Object, patch client.Patch) {
+	Expect(patch.Type()).To(Equal(types.MergePatchType))
+	data, err := patch.Data(obj)
+	Expect(err).NotTo(HaveOccurred())
+	changes := map[string]map[string]interface{}{}
+	Expect(json.Unmarshal(data, &changes)).To(Succeed())
+	Expect(changes).To(HaveLen(1))
+	Expect(changes["metadata"]).To(HaveKey("finalizers"))
+	Expect(changes["metadata"]).To(HaveKey("resourceVersion"))
+}
+
+var _ = Describe("FleetNotification Status Controller", func() {
+	var (
+		mockClient            *clientmocks.MockClient
+		mockStatusWriter      *clientmocks.MockStatusWriter
+		mockCtrl              *gomock.Controller
+		statusReconciler      *fleetnotification.ManagedFleetNotificationStatusReconciler
+		testFleetNotification *ocmagentv1alpha1.ManagedFleetNotification
+		testRecords           *ocmagentv1alpha1.ManagedFleetNotificationRecordList
+		lastSent              metav1.Time
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		mockStatusWriter = clientmocks.NewMockStatusWriter(mockCtrl)
+		statusReconciler = &fleetnotification.ManagedFleetNotificationStatusReconciler{
+			Client: mockClient,
+			Scheme: testconst.Scheme,
+		}
+		lastSent = metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		testFleetNotification = &ocmagentv1alpha1.ManagedFleetNotification{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            testconst.MfnNamespacedName.Name,
+				Namespace:       testconst.MfnNamespacedName.Namespace,
+				UID:             "test-mfn-uid",
+				ResourceVersion: "1",
+				Finalizers:      []string{ctrlconst.ReconcileFleetNotificationFinalizer},
+			},
+			Spec: ocmagentv1alpha1.ManagedFleetNotificationSpec{
+				FleetNotification: ocmagentv1alpha1.FleetNotification{Name: testNotificationName},
+			},
+		}
+		testRecords = &ocmagentv1alpha1.ManagedFleetNotificationRecordList{
+			Items: []ocmagentv1alpha1.ManagedFleetNotificationRecord{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "record-a", Namespace: testconst.MfnNamespacedName.Namespace},
+					Status: ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+						ManagementCluster: testManagementCluster,
+						NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+							{
+								NotificationName: testNotificationName,
+								NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+									{HostedClusterID: "hc-1", FiringNotificationSentCount: 2, ResolvedNotificationSentCount: 1, LastTransitionTime: &lastSent},
+									{HostedClusterID: "hc-2", FiringNotificationSentCount: 1},
+								},
+							},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "record-b", Namespace: testconst.MfnNamespacedName.Namespace},
+					Status: ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{
+						ManagementCluster: "other-mc",
+						NotificationRecordByName: []ocmagentv1alpha1.NotificationRecordByName{
+							{
+								NotificationName: testNotificationName,
+								NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+									{HostedClusterID: "hc-3", FiringNotificationSentCount: 1, LastTransitionTime: &metav1.Time{Time: lastSent.Add(-time.Hour)}},
+								},
+							},
+							{
+								NotificationName: "other-notification",
+								NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+									{HostedClusterID: "hc-4", FiringNotificationSentCount: 5},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("Reconciling the status of a ManagedFleetNotification", func() {
+		It("summarises the records of the notification", func() {
+			var updated *ocmagentv1alpha1.ManagedFleetNotification
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace(testconst.MfnNamespacedName.Namespace)).SetArg(1, *testRecords),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+						updated = obj.(*ocmagentv1alpha1.ManagedFleetNotification)
+						return nil
+					}),
+			)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Status).To(Equal(ocmagentv1alpha1.ManagedFleetNotificationStatus{
+				HostedClusters:                3,
+				FiringNotificationSentCount:   4,
+				ResolvedNotificationSentCount: 1,
+				LastSentTime:                  &lastSent,
+			}))
+		})
+		It("does not update an unchanged status", func() {
+			testFleetNotification.Status = testFleetNotification.SummarizeNotificationRecords(testRecords.Items)
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *testRecords),
+			)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+		It("ignores a deleted ManagedFleetNotification", func() {
+			mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).Return(
+				k8serrs.NewNotFound(schema.GroupResource{}, testconst.MfnNamespacedName.Name))
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+		It("returns the error when the records cannot be listed", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+			)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).To(MatchError("fake error"))
+		})
+	})
+
+	Context("Adding the finalizer", func() {
+		It("applies the finalizer before updating the status", func() {
+			testFleetNotification.Finalizers = nil
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification),
+				mockClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+						Expect(obj.GetFinalizers()).To(ConsistOf(ctrlconst.ReconcileFleetNotificationFinalizer))
+						expectFinalizerPatch(obj, patch)
+						return nil
+					}),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *testRecords),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()),
+			)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("Deleting a ManagedFleetNotification", func() {
+		var shard *ocmagentv1alpha1.ManagedFleetNotificationRecord
+
+		BeforeEach(func() {
+			now := metav1.Now()
+			testFleetNotification.DeletionTimestamp = &now
+			// record-a is a shard of the notification, record-b has not been migrated to shards yet
+			shard = &testRecords.Items[0]
+			shard.Labels = map[string]string{
+				ocmagentv1alpha1.ManagementClusterLabel:    testManagementCluster,
+				ocmagentv1alpha1.NotificationNameHashLabel: "test",
+			}
+		})
+
+		It("removes the records of the notification and then the finalizer", func() {
+			localmetrics.UpdateMetricFleetNotificationRecordItems(testManagementCluster, testNotificationName, 2)
+			removedBefore := testutil.ToFloat64(localmetrics.MetricFleetNotificationRecordItemsRemoved.WithLabelValues(
+				testManagementCluster, testNotificationName, localmetrics.RecordItemRemovalReasonNotificationDeleted))
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification),
+				mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&ocmagentv1alpha1.ManagedFleetNotificationList{}), gomock.Any()).SetArg(1,
+					ocmagentv1alpha1.ManagedFleetNotificationList{Items: []ocmagentv1alpha1.ManagedFleetNotification{*testFleetNotification}}),
+				mockClient.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&ocmagentv1alpha1.ManagedFleetNotificationRecordList{}), gomock.Any()).SetArg(1, *testRecords),
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						Expect(obj.GetName()).To(Equal("record-a"))
+						return nil
+					}),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+						record := obj.(*ocmagentv1alpha1.ManagedFleetNotificationRecord)
+						Expect(record.Name).To(Equal("record-b"))
+						Expect(record.Status.NotificationRecordByName).To(HaveLen(1))
+						Expect(record.Status.NotificationRecordByName[0].NotificationName).To(Equal("other-notification"))
+						return nil
+					}),
+				mockClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+						Expect(obj.GetFinalizers()).To(BeEmpty())
+						expectFinalizerPatch(obj, patch)
+						return nil
+					}),
+			)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testutil.ToFloat64(localmetrics.MetricFleetNotificationRecordItemsRemoved.WithLabelValues(
+				testManagementCluster, testNotificationName, localmetrics.RecordItemRemovalReasonNotificationDeleted))).To(Equal(removedBefore + 2))
+			// The item count of the notification was already removed
+			Expect(localmetrics.MetricFleetNotificationRecordItems.Delete(prometheus.Labels{
+				"management_cluster": testManagementCluster, "notification_name": testNotificationName})).To(BeFalse())
+		})
+		It("keeps the records when another ManagedFleetNotification defines the notification", func() {
+			other := testFleetNotification.DeepCopy()
+			other.Name = "duplicate"
+			other.UID = "duplicate-uid"
+			other.DeletionTimestamp = nil
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1,
+					ocmagentv1alpha1.ManagedFleetNotificationList{Items: []ocmagentv1alpha1.ManagedFleetNotification{*testFleetNotification, *other}}),
+				mockClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()),
+			)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+		It("keeps the finalizer when a record cannot be updated", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(1, *testRecords),
+				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(
+					k8serrs.NewConflict(schema.GroupResource{}, "record-b", fmt.Errorf("fake conflict"))),
+			)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(k8serrs.IsConflict(err)).To(BeTrue())
+		})
+		It("does nothing once the finalizer is removed", func() {
+			testFleetNotification.Finalizers = nil
+			mockClient.EXPECT().Get(gomock.Any(), testconst.MfnNamespacedName, gomock.Any()).SetArg(2, *testFleetNotification)
+			_, err := statusReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MfnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("Mapping a record to ManagedFleetNotifications", func() {
+		It("enqueues the ManagedFleetNotifications of the notifications in the record", func() {
+			other := testFleetNotification.DeepCopy()
+			other.Name = "unrelated"
+			other.Spec.FleetNotification.Name = "unrelated-notification"
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any(), client.InNamespace(testconst.MfnNamespacedName.Namespace)).SetArg(1,
+				ocmagentv1alpha1.ManagedFleetNotificationList{Items: []ocmagentv1alpha1.ManagedFleetNotification{*testFleetNotification, *other}})
+			requests := fleetnotification.EnqueueFleetNotificationsForRecord(statusReconciler, testconst.Context, &testRecords.Items[0])
+			Expect(requests).To(Equal([]reconcile.Request{{NamespacedName: testconst.MfnNamespacedName}}))
+		})
+		It("does not enqueue anything for an empty record", func() {
+			requests := fleetnotification.EnqueueFleetNotificationsForRecord(statusReconciler, testconst.Context, &ocmagentv1alpha1.ManagedFleetNotificationRecord{})
+			Expect(requests).To(BeEmpty())
+		})
+	})
+})
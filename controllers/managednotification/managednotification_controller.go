@@ -0,0 +1,112 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managednotification
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+const (
+	reasonNotificationsValid   = "NotificationsValid"
+	reasonNotificationsInvalid = "NotificationsInvalid"
+)
+
+// ManagedNotificationReconciler reconciles a ManagedNotification object
+type ManagedNotificationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+var log = logf.Log.WithName("controller_managednotification")
+
+var _ reconcile.Reconciler = &ManagedNotificationReconciler{}
+
+//+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=managednotifications,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=managednotifications/status,verbs=get;update;patch
+
+// Reconcile validates the notifications of a ManagedNotification, reports the result
+// as the Ready condition and prunes notification records that no longer have a
+// matching notification.
+func (r *ManagedNotificationReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling ManagedNotification")
+
+	mn := ocmagentv1alpha1.ManagedNotification{}
+	err := r.Get(ctx, request.NamespacedName, &mn)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	original := mn.Status.DeepCopy()
+
+	readyCondition := metav1.Condition{
+		Type:               ocmagentv1alpha1.ManagedNotificationConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonNotificationsValid,
+		Message:            "All notifications are valid",
+		ObservedGeneration: mn.Generation,
+	}
+	if errs := mn.Validate(); len(errs) > 0 {
+		reqLogger.Info("ManagedNotification contains invalid notifications", "errors", errs.ToAggregate().Error())
+		readyCondition.Status = metav1.ConditionFalse
+		readyCondition.Reason = reasonNotificationsInvalid
+		readyCondition.Message = errs.ToAggregate().Error()
+	}
+	meta.SetStatusCondition(&mn.Status.Conditions, readyCondition)
+
+	for _, name := range mn.PruneNotificationRecords() {
+		reqLogger.Info(fmt.Sprintf("Notification %s no longer exists, pruning its notification record", name))
+	}
+
+	if equality.Semantic.DeepEqual(*original, mn.Status) {
+		return reconcile.Result{}, nil
+	}
+
+	// The update is guarded by the resourceVersion, so a conflicting write of the
+	// notification records by the OCM Agent results in a retry rather than data loss.
+	if err := r.Status().Update(ctx, &mn); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ManagedNotificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ocmagentv1alpha1.ManagedNotification{}).
+		// Status is written by the OCM Agent on every notification sent, only spec changes are of interest
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
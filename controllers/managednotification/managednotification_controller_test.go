@@ -0,0 +1,165 @@
+package managednotification_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8serrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"go.uber.org/mock/gomock"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	"github.com/openshift/ocm-agent-operator/controllers/managednotification"
+	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+)
+
+var _ = Describe("ManagedNotification Controller", func() {
+	var (
+		mockClient                    *clientmocks.MockClient
+		mockStatusWriter              *clientmocks.MockStatusWriter
+		mockCtrl                      *gomock.Controller
+		managedNotificationReconciler *managednotification.ManagedNotificationReconciler
+		testManagedNotification       *ocmagentv1alpha1.ManagedNotification
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		mockStatusWriter = clientmocks.NewMockStatusWriter(mockCtrl)
+		managedNotificationReconciler = &managednotification.ManagedNotificationReconciler{
+			Client: mockClient,
+			Scheme: testconst.Scheme,
+		}
+		testManagedNotification = &ocmagentv1alpha1.ManagedNotification{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       testconst.MnNamespacedName.Name,
+				Namespace:  testconst.MnNamespacedName.Namespace,
+				Generation: 2,
+			},
+			Spec: ocmagentv1alpha1.ManagedNotificationSpec{
+				Notifications: []ocmagentv1alpha1.Notification{
+					{
+						Name:         "test-notification",
+						Summary:      "Test Summary",
+						ActiveDesc:   "Test Firing",
+						ResolvedDesc: "Test Resolved",
+						References:   []ocmagentv1alpha1.NotificationReferenceType{"https://docs.example.com"},
+						Severity:     ocmagentv1alpha1.SeverityInfo,
+						ResendWait:   1,
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	When("the ManagedNotification does not exist", func() {
+		It("does nothing", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MnNamespacedName, gomock.Any()).Times(1).Return(
+					k8serrs.NewNotFound(schema.GroupResource{}, testconst.MnNamespacedName.Name)),
+			)
+			_, err := managedNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("all notifications are valid", func() {
+		It("sets the Ready condition", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MnNamespacedName, gomock.Any()).Times(1).SetArg(2, *testManagedNotification),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, mn *ocmagentv1alpha1.ManagedNotification, opts ...client.SubResourceUpdateOption) error {
+						c := meta.FindStatusCondition(mn.Status.Conditions, ocmagentv1alpha1.ManagedNotificationConditionReady)
+						Expect(c).NotTo(BeNil())
+						Expect(c.Status).To(Equal(metav1.ConditionTrue))
+						Expect(c.ObservedGeneration).To(Equal(int64(2)))
+						return nil
+					}),
+			)
+			_, err := managedNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not update an unchanged status", func() {
+			meta.SetStatusCondition(&testManagedNotification.Status.Conditions, metav1.Condition{
+				Type:               ocmagentv1alpha1.ManagedNotificationConditionReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             "NotificationsValid",
+				Message:            "All notifications are valid",
+				ObservedGeneration: 2,
+			})
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MnNamespacedName, gomock.Any()).Times(1).SetArg(2, *testManagedNotification),
+			)
+			_, err := managedNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("the notifications are invalid", func() {
+		BeforeEach(func() {
+			testManagedNotification.Spec.Notifications = append(testManagedNotification.Spec.Notifications,
+				ocmagentv1alpha1.Notification{
+					Name:         "test-notification",
+					Summary:      "",
+					ResolvedDesc: "Resolved without firing",
+					References:   []ocmagentv1alpha1.NotificationReferenceType{"ftp://docs.example.com"},
+				})
+		})
+		It("marks the ManagedNotification as not ready", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MnNamespacedName, gomock.Any()).Times(1).SetArg(2, *testManagedNotification),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, mn *ocmagentv1alpha1.ManagedNotification, opts ...client.SubResourceUpdateOption) error {
+						c := meta.FindStatusCondition(mn.Status.Conditions, ocmagentv1alpha1.ManagedNotificationConditionReady)
+						Expect(c).NotTo(BeNil())
+						Expect(c.Status).To(Equal(metav1.ConditionFalse))
+						Expect(c.Reason).To(Equal("NotificationsInvalid"))
+						Expect(c.Message).To(ContainSubstring("spec.notifications[1].name"))
+						Expect(c.Message).To(ContainSubstring("spec.notifications[1].summary"))
+						Expect(c.Message).To(ContainSubstring("spec.notifications[1].activeBody"))
+						Expect(c.Message).To(ContainSubstring("spec.notifications[1].references[0]"))
+						return nil
+					}),
+			)
+			_, err := managedNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	When("a notification record has no matching notification", func() {
+		BeforeEach(func() {
+			testManagedNotification.Status.NotificationRecords = ocmagentv1alpha1.NotificationRecords{
+				{Name: "test-notification", ServiceLogSentCount: 1},
+				{Name: "removed-notification", ServiceLogSentCount: 3},
+			}
+		})
+		It("prunes the stale record", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), testconst.MnNamespacedName, gomock.Any()).Times(1).SetArg(2, *testManagedNotification),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+					func(ctx context.Context, mn *ocmagentv1alpha1.ManagedNotification, opts ...client.SubResourceUpdateOption) error {
+						Expect(mn.Status.NotificationRecords).To(HaveLen(1))
+						Expect(mn.Status.NotificationRecords[0].Name).To(Equal("test-notification"))
+						return nil
+					}),
+			)
+			_, err := managedNotificationReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.MnNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,7 @@
+package ocmagent
+
+// Expose the watch helpers to the external test package
+var (
+	EnqueueAllOcmAgents = (*OcmAgentReconciler).enqueueAllOcmAgents
+	IsPullSecret        = isPullSecret
+)
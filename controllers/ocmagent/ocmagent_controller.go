@@ -24,6 +24,7 @@ import (
 	netv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 
+	oconfigv1 "github.com/openshift/api/config/v1"
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	ctrlconst "github.com/openshift/ocm-agent-operator/pkg/consts/controller"
 	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
@@ -32,9 +33,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -52,6 +56,7 @@ var _ reconcile.Reconciler = &OcmAgentReconciler{}
 //+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=ocmagents,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=ocmagents/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=ocmagents/finalizers,verbs=update
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -75,6 +80,7 @@ func (r *OcmAgentReconciler) Reconcile(ctx context.Context, request reconcile.Re
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			localmetrics.UpdateMetricOcmAgentResourceAbsent()
+			localmetrics.ResetMetricOcmAgentPaused(request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -91,6 +97,8 @@ func (r *OcmAgentReconciler) Reconcile(ctx context.Context, request reconcile.Re
 
 	// Is the OCMAgent being deleted?
 	if !instance.DeletionTimestamp.IsZero() {
+		// The OCM Agent resources are removed even when reconciliation is paused
+		localmetrics.ResetMetricOcmAgentPaused(instance.Name)
 		reqLogger.V(2).Info("Entering EnsureOCMAgentResourcesAbsent")
 		err := oaohandler.EnsureOCMAgentResourcesAbsent(ctx, instance)
 		if err != nil {
@@ -106,12 +114,25 @@ func (r *OcmAgentReconciler) Reconcile(ctx context.Context, request reconcile.Re
 			}
 		}
 		return reconcile.Result{}, nil
+	} else if instance.IsPaused() {
+		// Leave the OCM Agent resources as they are until the paused annotation is removed
+		reqLogger.Info("Reconciliation of the OCMAgent is paused", "annotation", ocmagentv1alpha1.PausedAnnotation)
+		localmetrics.UpdateMetricOcmAgentPaused(instance.Name)
+		if err := r.updateStatus(ctx, &instance, nil); err != nil {
+			reqLogger.Error(err, "Failed to update OCMAgent status. Will retry on next reconcile.")
+			return reconcile.Result{}, err
+		}
 	} else {
+		localmetrics.ResetMetricOcmAgentPaused(instance.Name)
+
 		// There needs to be an OCM Agent
 		reqLogger.V(2).Info("Entering EnsureOCMAgentResourcesExist")
 		err := oaohandler.EnsureOCMAgentResourcesExist(ctx, instance)
 		if err != nil {
 			reqLogger.Error(err, "Failed to create OCMAgent. Will retry on next reconcile.")
+			if statusErr := r.updateStatus(ctx, &instance, err); statusErr != nil {
+				reqLogger.Error(statusErr, "Failed to update OCMAgent status")
+			}
 			return reconcile.Result{}, err
 		}
 
@@ -123,19 +144,20 @@ func (r *OcmAgentReconciler) Reconcile(ctx context.Context, request reconcile.Re
 				return reconcile.Result{}, err
 			}
 		}
+
+		if err := r.updateStatus(ctx, &instance, nil); err != nil {
+			reqLogger.Error(err, "Failed to update OCMAgent status. Will retry on next reconcile.")
+			return reconcile.Result{}, err
+		}
 	}
 
-	// Periodically reconcile to check for pull-secret changes
-	// Since we can't watch openshift-config/pull-secret (due to RBAC/cache issues),
-	// we reconcile every 5 minutes to detect changes
-	return reconcile.Result{RequeueAfter: ctrlconst.SyncPeriodDefault}, nil
+	return reconcile.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// The cluster-wide objects the OCM Agent resources are built from are watched as well, which
+// requires the manager cache to be configured with CacheByObject.
 func (r *OcmAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// Note: We use periodic reconciliation instead of watching pull-secret
-	// due to RBAC/cache issues with openshift-config namespace
-
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ocmagentv1alpha1.OcmAgent{}).
 		Owns(&netv1.NetworkPolicy{}).
@@ -145,5 +167,10 @@ func (r *OcmAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ConfigMap{}).
 		Owns(&monitorv1.ServiceMonitor{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllOcmAgents),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isPullSecret))).
+		Watches(&oconfigv1.Proxy{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllOcmAgents)).
+		Watches(&oconfigv1.ClusterVersion{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllOcmAgents),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
@@ -2,11 +2,17 @@ package ocmagent_test
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"go.uber.org/mock/gomock"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -16,14 +22,17 @@ import (
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	"github.com/openshift/ocm-agent-operator/controllers/ocmagent"
 	ctrlconst "github.com/openshift/ocm-agent-operator/pkg/consts/controller"
+	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
 	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+	"github.com/openshift/ocm-agent-operator/pkg/ocmagenthandler"
 	ocmagenthandlermocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/ocmagenthandler"
 )
 
 var _ = Describe("OCMAgent Controller", func() {
 	var (
 		mockClient                 *clientmocks.MockClient
+		mockStatusWriter           *clientmocks.MockStatusWriter
 		mockCtrl                   *gomock.Controller
 		mockOcmAgentHandler        *ocmagenthandlermocks.MockOCMAgentHandler
 		ocmAgentReconciler         *ocmagent.OcmAgentReconciler
@@ -34,6 +43,7 @@ var _ = Describe("OCMAgent Controller", func() {
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockClient = clientmocks.NewMockClient(mockCtrl)
+		mockStatusWriter = clientmocks.NewMockStatusWriter(mockCtrl)
 		mockOcmAgentHandler = ocmagenthandlermocks.NewMockOCMAgentHandler(mockCtrl)
 		mockOcmAgentHandlerBuilder = ocmagenthandlermocks.NewMockOcmAgentHandlerBuilder(mockCtrl)
 		ocmAgentReconciler = &ocmagent.OcmAgentReconciler{
@@ -56,6 +66,27 @@ var _ = Describe("OCMAgent Controller", func() {
 		})
 
 		When("An OCM Agent needs to be created", func() {
+			var testDeployment appsv1.Deployment
+			BeforeEach(func() {
+				replicas := int32(1)
+				testDeployment = appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testOcmAgent.Name,
+						Namespace: oah.OCMAgentNamespace,
+					},
+					Spec: appsv1.DeploymentSpec{
+						Replicas: &replicas,
+					},
+					Status: appsv1.DeploymentStatus{
+						Replicas:          1,
+						UpdatedReplicas:   1,
+						AvailableReplicas: 1,
+						Conditions: []appsv1.DeploymentCondition{
+							{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+						},
+					},
+				}
+			})
 			It("Creates an OCM Agent", func() {
 				gomock.InOrder(
 					mockClient.EXPECT().Get(gomock.Any(), testconst.OCMAgentNamespacedName, gomock.Any()).Times(1).SetArg(2, *testOcmAgent),
@@ -66,6 +97,20 @@ var _ = Describe("OCMAgent Controller", func() {
 							Expect(o.Finalizers).To(ContainElement(ctrlconst.ReconcileOCMAgentFinalizer))
 							return nil
 						}),
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(2, testDeployment),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, o *ocmagentv1alpha1.OcmAgent, opts ...client.SubResourceUpdateOption) error {
+							Expect(o.Status.ServiceStatus).To(Equal(ocmagentv1alpha1.OcmAgentServiceStatusAvailable))
+							Expect(o.Status.AvailableReplicas).To(Equal(int32(1)))
+							Expect(meta.IsStatusConditionTrue(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionAvailable)).To(BeTrue())
+							Expect(meta.IsStatusConditionFalse(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionProgressing)).To(BeTrue())
+							Expect(meta.IsStatusConditionFalse(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionDegraded)).To(BeTrue())
+							Expect(meta.IsStatusConditionTrue(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionPullSecretValid)).To(BeTrue())
+							Expect(meta.IsStatusConditionTrue(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionProxyConfigured)).To(BeTrue())
+							Expect(meta.IsStatusConditionFalse(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionPaused)).To(BeTrue())
+							return nil
+						}),
 				)
 				_, err := ocmAgentReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.OCMAgentNamespacedName})
 				Expect(err).To(BeNil())
@@ -73,6 +118,59 @@ var _ = Describe("OCMAgent Controller", func() {
 			})
 		})
 
+		When("The OCM Agent resources cannot be ensured", func() {
+			It("Reports a degraded status with an invalid pull secret", func() {
+				pullSecretErr := fmt.Errorf("%w: %w", ocmagenthandler.ErrPullSecretInvalid,
+					k8serrs.NewNotFound(schema.GroupResource{}, oah.PullSecretNamespacedName.Name))
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.OCMAgentNamespacedName, gomock.Any()).Times(1).SetArg(2, *testOcmAgent),
+					mockOcmAgentHandlerBuilder.EXPECT().New().Return(mockOcmAgentHandler, nil),
+					mockOcmAgentHandler.EXPECT().EnsureOCMAgentResourcesExist(gomock.Any(), *testOcmAgent).Times(1).Return(pullSecretErr),
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(
+						k8serrs.NewNotFound(schema.GroupResource{}, testOcmAgent.Name)),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, o *ocmagentv1alpha1.OcmAgent, opts ...client.SubResourceUpdateOption) error {
+							Expect(o.Status.ServiceStatus).To(Equal(ocmagentv1alpha1.OcmAgentServiceStatusDegraded))
+							Expect(o.Status.AvailableReplicas).To(Equal(int32(0)))
+							Expect(meta.IsStatusConditionTrue(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionDegraded)).To(BeTrue())
+							Expect(meta.IsStatusConditionFalse(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionPullSecretValid)).To(BeTrue())
+							Expect(meta.IsStatusConditionFalse(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionAvailable)).To(BeTrue())
+							Expect(meta.FindStatusCondition(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionProxyConfigured)).To(BeNil())
+							return nil
+						}),
+				)
+				_, err := ocmAgentReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.OCMAgentNamespacedName})
+				Expect(err).To(MatchError(ocmagenthandler.ErrPullSecretInvalid))
+			})
+		})
+
+		When("Reconciliation of the OCM Agent is paused", func() {
+			BeforeEach(func() {
+				testOcmAgent.Annotations = map[string]string{ocmagentv1alpha1.PausedAnnotation: "true"}
+			})
+			It("Leaves the OCM Agent resources alone and reports a paused status", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testconst.OCMAgentNamespacedName, gomock.Any()).Times(1).SetArg(2, *testOcmAgent),
+					mockOcmAgentHandlerBuilder.EXPECT().New().Return(mockOcmAgentHandler, nil),
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(
+						k8serrs.NewNotFound(schema.GroupResource{}, testOcmAgent.Name)),
+					mockClient.EXPECT().Status().Return(mockStatusWriter),
+					mockStatusWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, o *ocmagentv1alpha1.OcmAgent, opts ...client.SubResourceUpdateOption) error {
+							Expect(meta.IsStatusConditionTrue(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionPaused)).To(BeTrue())
+							Expect(meta.FindStatusCondition(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionDegraded)).To(BeNil())
+							Expect(meta.FindStatusCondition(o.Status.Conditions, ocmagentv1alpha1.OcmAgentConditionPullSecretValid)).To(BeNil())
+							return nil
+						}),
+				)
+				mockOcmAgentHandler.EXPECT().EnsureOCMAgentResourcesExist(gomock.Any(), gomock.Any()).Times(0)
+				mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+				_, err := ocmAgentReconciler.Reconcile(testconst.Context, reconcile.Request{NamespacedName: testconst.OCMAgentNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
 		When("An OCM Agent needs to be deleted", func() {
 			BeforeEach(func() {
 				testOcmAgent.DeletionTimestamp = &metav1.Time{Time: time.Now()}
@@ -0,0 +1,199 @@
+package ocmagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
+	"github.com/openshift/ocm-agent-operator/pkg/ocmagenthandler"
+)
+
+const (
+	reasonReconcileSucceeded  = "ReconcileSucceeded"
+	reasonReconcileFailed     = "ReconcileFailed"
+	reasonPullSecretValid     = "PullSecretValid"
+	reasonPullSecretInvalid   = "PullSecretInvalid"
+	reasonProxyInjected       = "ProxyInjected"
+	reasonNoClusterProxy      = "NoClusterProxy"
+	reasonProxyUnavailable    = "ProxyUnavailable"
+	reasonDeploymentNotFound  = "DeploymentNotFound"
+	reasonMinimumReplicas     = "MinimumReplicasAvailable"
+	reasonNoReplicasAvailable = "NoReplicasAvailable"
+	reasonRollingOut          = "RollingOut"
+	reasonRolloutComplete     = "RolloutComplete"
+	reasonPausedByAnnotation  = "PausedByAnnotation"
+	reasonReconcileActive     = "ReconcileActive"
+)
+
+const (
+	httpProxyEnvName  = "HTTP_PROXY"
+	httpsProxyEnvName = "HTTPS_PROXY"
+	// defaultDeploymentReplicas is the replica count the API server defaults a Deployment to
+	defaultDeploymentReplicas = int32(1)
+)
+
+// updateStatus refreshes the status of the OcmAgent from its owned Deployment and
+// the outcome of the last reconcile, and writes it back only when it has changed.
+func (r *OcmAgentReconciler) updateStatus(ctx context.Context, instance *ocmagentv1alpha1.OcmAgent, reconcileErr error) error {
+	var deployment *appsv1.Deployment
+	foundDeployment := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, oah.BuildNamespacedName(instance.Name), foundDeployment); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		deployment = foundDeployment
+	}
+
+	status := instance.Status.DeepCopy()
+	buildOcmAgentStatus(status, instance, deployment, reconcileErr)
+	if equality.Semantic.DeepEqual(*status, instance.Status) {
+		return nil
+	}
+
+	instance.Status = *status
+	return r.Client.Status().Update(ctx, instance)
+}
+
+// buildOcmAgentStatus populates the supplied status in place. Conditions that cannot
+// be determined from a failed or paused reconcile are left untouched.
+func buildOcmAgentStatus(status *ocmagentv1alpha1.OcmAgentStatus, ocmAgent *ocmagentv1alpha1.OcmAgent,
+	deployment *appsv1.Deployment, reconcileErr error) {
+	generation := ocmAgent.Generation
+	status.ObservedGeneration = generation
+
+	setCondition := func(conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: generation,
+		})
+	}
+
+	paused := ocmAgent.IsPaused()
+	if paused {
+		setCondition(ocmagentv1alpha1.OcmAgentConditionPaused, metav1.ConditionTrue, reasonPausedByAnnotation,
+			fmt.Sprintf("Reconciliation is suspended by the %s annotation", ocmagentv1alpha1.PausedAnnotation))
+	} else {
+		setCondition(ocmagentv1alpha1.OcmAgentConditionPaused, metav1.ConditionFalse, reasonReconcileActive,
+			"The OCM Agent resources are reconciled")
+	}
+
+	// Degraded reflects the outcome of ensuring the OCM Agent resources
+	switch {
+	case paused:
+	case reconcileErr != nil:
+		setCondition(ocmagentv1alpha1.OcmAgentConditionDegraded, metav1.ConditionTrue, reasonReconcileFailed, reconcileErr.Error())
+	default:
+		setCondition(ocmagentv1alpha1.OcmAgentConditionDegraded, metav1.ConditionFalse, reasonReconcileSucceeded,
+			"All OCM Agent resources are reconciled")
+	}
+
+	// The pull secret is only consumed outside of fleet mode. The access token secret is the
+	// first resource to be ensured, so any other failure means the pull secret was usable.
+	switch {
+	case paused:
+	case ocmAgent.Spec.FleetMode:
+		meta.RemoveStatusCondition(&status.Conditions, ocmagentv1alpha1.OcmAgentConditionPullSecretValid)
+	case errors.Is(reconcileErr, ocmagenthandler.ErrPullSecretInvalid):
+		setCondition(ocmagentv1alpha1.OcmAgentConditionPullSecretValid, metav1.ConditionFalse, reasonPullSecretInvalid, reconcileErr.Error())
+	default:
+		setCondition(ocmagentv1alpha1.OcmAgentConditionPullSecretValid, metav1.ConditionTrue, reasonPullSecretValid,
+			"OCM access token was sourced from the cluster pull secret")
+	}
+
+	switch {
+	case paused:
+	case errors.Is(reconcileErr, ocmagenthandler.ErrProxyUnavailable):
+		setCondition(ocmagentv1alpha1.OcmAgentConditionProxyConfigured, metav1.ConditionFalse, reasonProxyUnavailable, reconcileErr.Error())
+	case reconcileErr == nil && deployment != nil:
+		if deploymentHasProxy(deployment, ocmAgent.Name) {
+			setCondition(ocmagentv1alpha1.OcmAgentConditionProxyConfigured, metav1.ConditionTrue, reasonProxyInjected,
+				"Cluster proxy settings are injected into the OCM Agent deployment")
+		} else {
+			setCondition(ocmagentv1alpha1.OcmAgentConditionProxyConfigured, metav1.ConditionTrue, reasonNoClusterProxy,
+				"No cluster-wide proxy is configured")
+		}
+	}
+
+	if deployment == nil {
+		status.AvailableReplicas = 0
+		setCondition(ocmagentv1alpha1.OcmAgentConditionAvailable, metav1.ConditionFalse, reasonDeploymentNotFound,
+			"The OCM Agent deployment does not exist")
+		setCondition(ocmagentv1alpha1.OcmAgentConditionProgressing, metav1.ConditionUnknown, reasonDeploymentNotFound,
+			"The OCM Agent deployment does not exist")
+	} else {
+		desired := defaultDeploymentReplicas
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		depStatus := deployment.Status
+		status.AvailableReplicas = depStatus.AvailableReplicas
+
+		if deploymentAvailable(deployment) {
+			setCondition(ocmagentv1alpha1.OcmAgentConditionAvailable, metav1.ConditionTrue, reasonMinimumReplicas,
+				fmt.Sprintf("%d of %d replicas are available", depStatus.AvailableReplicas, desired))
+		} else {
+			setCondition(ocmagentv1alpha1.OcmAgentConditionAvailable, metav1.ConditionFalse, reasonNoReplicasAvailable,
+				fmt.Sprintf("%d of %d replicas are available", depStatus.AvailableReplicas, desired))
+		}
+
+		if depStatus.ObservedGeneration < deployment.Generation ||
+			depStatus.UpdatedReplicas < desired ||
+			depStatus.AvailableReplicas < desired ||
+			depStatus.Replicas > depStatus.UpdatedReplicas {
+			setCondition(ocmagentv1alpha1.OcmAgentConditionProgressing, metav1.ConditionTrue, reasonRollingOut,
+				fmt.Sprintf("%d of %d replicas are updated and %d are available", depStatus.UpdatedReplicas, desired, depStatus.AvailableReplicas))
+		} else {
+			setCondition(ocmagentv1alpha1.OcmAgentConditionProgressing, metav1.ConditionFalse, reasonRolloutComplete,
+				"The OCM Agent deployment is fully rolled out")
+		}
+	}
+
+	switch {
+	case reconcileErr != nil:
+		status.ServiceStatus = ocmagentv1alpha1.OcmAgentServiceStatusDegraded
+	case !meta.IsStatusConditionTrue(status.Conditions, ocmagentv1alpha1.OcmAgentConditionAvailable):
+		status.ServiceStatus = ocmagentv1alpha1.OcmAgentServiceStatusUnavailable
+	case meta.IsStatusConditionTrue(status.Conditions, ocmagentv1alpha1.OcmAgentConditionProgressing):
+		status.ServiceStatus = ocmagentv1alpha1.OcmAgentServiceStatusProgressing
+	default:
+		status.ServiceStatus = ocmagentv1alpha1.OcmAgentServiceStatusAvailable
+	}
+}
+
+// deploymentAvailable reports whether the deployment has the minimum number of replicas available
+func deploymentAvailable(deployment *appsv1.Deployment) bool {
+	for _, c := range deployment.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return deployment.Status.AvailableReplicas > 0
+}
+
+// deploymentHasProxy reports whether the OCM Agent container has proxy environment variables set
+func deploymentHasProxy(deployment *appsv1.Deployment, containerName string) bool {
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if c.Name != containerName {
+			continue
+		}
+		for _, env := range c.Env {
+			if (env.Name == httpProxyEnvName || env.Name == httpsProxyEnvName) && env.Value != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package ocmagent
+
+import (
+	"context"
+
+	oconfigv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
+)
+
+// CacheByObject returns the cache configuration needed to watch the cluster-wide objects the
+// OCM Agent depends on. Each of them is restricted to the single object that is used, so that
+// the cache does not hold every secret of the cluster.
+func CacheByObject(operatorNamespace string) map[client.Object]cache.ByObject {
+	return map[client.Object]cache.ByObject{
+		&corev1.Secret{}: {
+			Namespaces: map[string]cache.Config{
+				operatorNamespace: {},
+				oah.PullSecretNamespacedName.Namespace: {
+					FieldSelector: fields.OneTermEqualSelector("metadata.name", oah.PullSecretNamespacedName.Name),
+				},
+			},
+		},
+		&oconfigv1.Proxy{}: {
+			Field: fields.OneTermEqualSelector("metadata.name", oah.ProxyNamespacedName.Name),
+		},
+		&oconfigv1.ClusterVersion{}: {
+			Field: fields.OneTermEqualSelector("metadata.name", oah.ClusterVersionNamespacedName.Name),
+		},
+	}
+}
+
+// enqueueAllOcmAgents maps a change to a cluster-wide object to a reconcile of every OcmAgent,
+// as any of them may be built from it
+func (r *OcmAgentReconciler) enqueueAllOcmAgents(ctx context.Context, _ client.Object) []reconcile.Request {
+	ocmAgents := &ocmagentv1alpha1.OcmAgentList{}
+	if err := r.Client.List(ctx, ocmAgents); err != nil {
+		log.Error(err, "Failed to list OCMAgents to reconcile")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(ocmAgents.Items))
+	for _, ocmAgent := range ocmAgents.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: ocmAgent.Namespace, Name: ocmAgent.Name},
+		})
+	}
+	return requests
+}
+
+// isPullSecret filters the secret watch to the cluster pull secret, as the other secrets
+// are owned by an OcmAgent and already handled through their owner reference
+func isPullSecret(obj client.Object) bool {
+	return obj.GetNamespace() == oah.PullSecretNamespacedName.Namespace &&
+		obj.GetName() == oah.PullSecretNamespacedName.Name
+}
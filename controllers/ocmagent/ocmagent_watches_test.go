@@ -0,0 +1,111 @@
+package ocmagent_test
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/mock/gomock"
+
+	oconfigv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	"github.com/openshift/ocm-agent-operator/controllers/ocmagent"
+	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
+	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+)
+
+var _ = Describe("OCMAgent Controller watches", func() {
+	var (
+		mockClient         *clientmocks.MockClient
+		mockCtrl           *gomock.Controller
+		ocmAgentReconciler *ocmagent.OcmAgentReconciler
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		ocmAgentReconciler = &ocmagent.OcmAgentReconciler{
+			Client: mockClient,
+			Scheme: testconst.Scheme,
+		}
+	})
+
+	Context("Building the cache configuration", func() {
+		It("restricts the cluster-wide objects to the ones the OCM Agent uses", func() {
+			byObject := ocmagent.CacheByObject("test-namespace")
+			Expect(byObject).To(HaveLen(3))
+			for obj, config := range byObject {
+				switch obj.(type) {
+				case *corev1.Secret:
+					Expect(config.Namespaces).To(HaveKey("test-namespace"))
+					Expect(config.Namespaces["test-namespace"].FieldSelector).To(BeNil())
+					Expect(config.Namespaces).To(HaveKey(oah.PullSecretNamespacedName.Namespace))
+					Expect(config.Namespaces[oah.PullSecretNamespacedName.Namespace].FieldSelector.String()).
+						To(Equal("metadata.name=" + oah.PullSecretNamespacedName.Name))
+				case *oconfigv1.Proxy:
+					Expect(config.Field.String()).To(Equal("metadata.name=" + oah.ProxyNamespacedName.Name))
+				case *oconfigv1.ClusterVersion:
+					Expect(config.Field.String()).To(Equal("metadata.name=" + oah.ClusterVersionNamespacedName.Name))
+				default:
+					Fail(fmt.Sprintf("unexpected object in cache configuration: %T", obj))
+				}
+			}
+		})
+	})
+
+	Context("Filtering secrets", func() {
+		It("matches the cluster pull secret", func() {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Namespace: oah.PullSecretNamespacedName.Namespace,
+				Name:      oah.PullSecretNamespacedName.Name,
+			}}
+			Expect(ocmagent.IsPullSecret(secret)).To(BeTrue())
+		})
+		It("ignores other secrets", func() {
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Namespace: testconst.OCMAgentNamespacedName.Namespace,
+				Name:      oah.PullSecretNamespacedName.Name,
+			}}
+			Expect(ocmagent.IsPullSecret(secret)).To(BeFalse())
+		})
+	})
+
+	Context("Mapping a cluster-wide object change", func() {
+		var proxy *oconfigv1.Proxy
+
+		BeforeEach(func() {
+			proxy = &oconfigv1.Proxy{ObjectMeta: metav1.ObjectMeta{Name: oah.ProxyNamespacedName.Name}}
+		})
+
+		It("enqueues every OcmAgent", func() {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, list *ocmagentv1alpha1.OcmAgentList, opts ...client.ListOption) error {
+					list.Items = []ocmagentv1alpha1.OcmAgent{
+						{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent-a"}},
+						{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent-b"}},
+					}
+					return nil
+				})
+			requests := ocmagent.EnqueueAllOcmAgents(ocmAgentReconciler, context.TODO(), proxy)
+			Expect(requests).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "agent-a"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "agent-b"}},
+			))
+		})
+
+		It("enqueues nothing when the OcmAgents cannot be listed", func() {
+			mockClient.EXPECT().List(gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error"))
+			requests := ocmagent.EnqueueAllOcmAgents(ocmAgentReconciler, context.TODO(), proxy)
+			Expect(requests).To(BeEmpty())
+		})
+	})
+})
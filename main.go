@@ -26,8 +26,11 @@ import (
 	"time"
 
 	"github.com/openshift/ocm-agent-operator/controllers/fleetnotification"
+	"github.com/openshift/ocm-agent-operator/controllers/managednotification"
 	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
 	"github.com/openshift/ocm-agent-operator/pkg/ocmagenthandler"
+	"github.com/openshift/ocm-agent-operator/pkg/preview"
+	"github.com/openshift/ocm-agent-operator/pkg/storagemigration"
 	"github.com/openshift/ocm-agent-operator/pkg/util/namespace"
 	"github.com/openshift/ocm-agent-operator/pkg/version"
 
@@ -35,6 +38,7 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -59,6 +63,7 @@ import (
 	osdmetrics "github.com/openshift/operator-custom-metrics/pkg/metrics"
 
 	ocmagentmanagedopenshiftiov1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	ocmagentmanagedopenshiftiov1beta1 "github.com/openshift/ocm-agent-operator/api/v1beta1"
 	"github.com/openshift/ocm-agent-operator/controllers/ocmagent"
 	//+kubebuilder:scaffold:imports
 )
@@ -78,6 +83,8 @@ var log = logf.Log.WithName("cmd")
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(ocmagentmanagedopenshiftiov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(ocmagentmanagedopenshiftiov1beta1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(oconfigv1.Install(scheme))
 	utilruntime.Must(monitorv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -91,16 +98,30 @@ func printVersion() {
 }
 
 func main() {
+	// The preview subcommand renders notifications locally and does not run the operator
+	if len(os.Args) > 1 && os.Args[1] == preview.CommandName {
+		os.Exit(preview.Run(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var withWebhookHTTP2 bool
+	var notificationRecordStaleTimeout time.Duration
+	var shardNotificationRecords bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&withWebhookHTTP2, "webhook-http2", false, "enables http2 for the webhook endpoint")
+	flag.DurationVar(&notificationRecordStaleTimeout, "notification-record-stale-timeout",
+		time.Duration(fleetnotification.NotificationRecordStaleTimeoutInHour)*time.Hour,
+		"How long after the resend wait has elapsed a fleet notification record is kept before it is removed as stale, "+
+			"unless its ManagedFleetNotification sets a staleTimeout.")
+	flag.BoolVar(&shardNotificationRecords, "shard-notification-records", false,
+		"Move the records of each fleet notification to a ManagedFleetNotificationRecord of its own and delete the "+
+			"unsharded record. Only enable once the OCM Agent writes to the record shards.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -146,6 +167,7 @@ func main() {
 			DefaultNamespaces: map[string]cache.Config{
 				operatorNS: {},
 			},
+			ByObject: ocmagent.CacheByObject(operatorNS),
 		},
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -172,20 +194,65 @@ func main() {
 	if err = (&ocmagent.OcmAgentReconciler{
 		Client:                 mgr.GetClient(),
 		Scheme:                 mgr.GetScheme(),
-		OCMAgentHandlerBuilder: ocmagenthandler.NewBuilder(handlerClient),
+		OCMAgentHandlerBuilder: ocmagenthandler.NewBuilder(handlerClient, mgr.GetEventRecorder("ocm-agent-operator")),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OcmAgent")
 		os.Exit(1)
 	}
 	if err = (&fleetnotification.ManagedFleetNotificationReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		StaleTimeout: notificationRecordStaleTimeout,
+		ShardRecords: shardNotificationRecords,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ManagedFleetNotification")
+		os.Exit(1)
+	}
+	if err = (&fleetnotification.ManagedFleetNotificationStatusReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "ManagedFleetNotification")
+		setupLog.Error(err, "unable to create controller", "controller", "ManagedFleetNotificationStatus")
+		os.Exit(1)
+	}
+	if err = (&managednotification.ManagedNotificationReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ManagedNotification")
 		os.Exit(1)
 	}
+	// Webhooks need serving certificates, so they can be disabled when running the operator locally
+	enableWebhooks := os.Getenv("ENABLE_WEBHOOKS") != "false"
+	if enableWebhooks {
+		if err = (&ocmagentmanagedopenshiftiov1alpha1.OcmAgent{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OcmAgent")
+			os.Exit(1)
+		}
+		if err = (&ocmagentmanagedopenshiftiov1beta1.OcmAgent{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OcmAgent", "version", "v1beta1")
+			os.Exit(1)
+		}
+		if err = (&ocmagentmanagedopenshiftiov1alpha1.ManagedNotification{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ManagedNotification")
+			os.Exit(1)
+		}
+		if err = (&ocmagentmanagedopenshiftiov1alpha1.ManagedFleetNotification{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ManagedFleetNotification")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	// Rewrite existing OcmAgents in the storage version once the conversion webhook is being served.
+	// An operator running without webhooks leaves the migration to the one deployed on the cluster.
+	if enableWebhooks {
+		if err := mgr.Add(storagemigration.NewMigrator(handlerClient)); err != nil {
+			setupLog.Error(err, "unable to add OcmAgent storage migration")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -1,13 +1,9 @@
 package controller
 
-import (
-	"time"
-)
-
 const (
-	// SyncPeriodDefault reconciles a sync period for each controller
-	SyncPeriodDefault = 5 * time.Minute
-
 	// ReconcileOCMAgentFinalizer defines the finalizer to apply to the OCM Agent resource
 	ReconcileOCMAgentFinalizer = "ocmagent.managed.openshift.io"
+	// ReconcileFleetNotificationFinalizer defines the finalizer to apply to the ManagedFleetNotification resource
+	// until the records of the notification are removed
+	ReconcileFleetNotificationFinalizer = "ocmagent.managed.openshift.io/fleet-notification-records"
 )
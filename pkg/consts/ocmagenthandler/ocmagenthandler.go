@@ -34,6 +34,15 @@ const (
 	OCMAgentServiceAccount = "ocm-agent"
 	// OCMAgentCommand is the name of the OCM Agent binary to run in the deployment
 	OCMAgentCommand = "ocm-agent"
+	// FieldManager is the field manager the operator applies the OCM Agent resources with
+	FieldManager = "ocm-agent-operator"
+	// LegacyFieldManager is the field manager the OCM Agent resources were created and updated with
+	// before the operator applied them, which is the name of the operator binary
+	LegacyFieldManager = "ocm-agent-operator"
+	// RestartFieldManager is the field manager the OCM Agent pods are restarted with
+	RestartFieldManager = "ocm-agent-operator-restart"
+	// ConfigHashAnnotation records the hash of the configuration the operator last applied to an OCM Agent resource
+	ConfigHashAnnotation = "ocmagent.managed.openshift.io/config-hash"
 
 	// OCMAgentServicePort is the port number to use for the OCM Agent Service
 	OCMAgentServicePort = 8081
@@ -99,10 +108,17 @@ var (
 		Name:      "ocm-agent",
 	}
 
+	// ProxyNamespacedName defines the name of the cluster-wide proxy configuration
 	ProxyNamespacedName = types.NamespacedName{
 		Namespace: "",
 		Name:      "cluster",
 	}
+
+	// ClusterVersionNamespacedName defines the name of the cluster version, which holds the cluster ID
+	ClusterVersionNamespacedName = types.NamespacedName{
+		Namespace: "",
+		Name:      "version",
+	}
 )
 
 // BuildNamespacedName returns the name and namespace intended for OCM Agent deployment resources
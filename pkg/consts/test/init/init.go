@@ -10,6 +10,8 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
+	monitorv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	"github.com/openshift/ocm-agent-operator/pkg/test"
 )
@@ -63,10 +65,21 @@ var (
 		Name:      "test-managedfleetnotificationrecord",
 		Namespace: "test-namespace",
 	}
+
+	MfnNamespacedName = types.NamespacedName{
+		Name:      "test-managedfleetnotification",
+		Namespace: "test-namespace",
+	}
+
+	MnNamespacedName = types.NamespacedName{
+		Name:      "test-managednotification",
+		Namespace: "test-namespace",
+	}
 )
 
 func setScheme(scheme *runtime.Scheme) *runtime.Scheme {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(ocmagentv1alpha1.SchemeBuilder.AddToScheme(scheme))
+	utilruntime.Must(monitorv1.AddToScheme(scheme))
 	return scheme
 }
@@ -1,12 +1,42 @@
 package localmetrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
-	metricsTag = "ocm_agent_operator"
-	nameLabel  = "ocmagent_name"
+	metricsTag   = "ocm_agent_operator"
+	nameLabel    = "ocmagent_name"
+	kindLabel    = "kind"
+	outcomeLabel = "outcome"
+
+	managementClusterLabel = "management_cluster"
+	notificationNameLabel  = "notification_name"
+	reasonLabel            = "reason"
+)
+
+// Outcomes of reconciling an OCM Agent resource
+const (
+	// ResourceOutcomeCreate is counted when a resource was created
+	ResourceOutcomeCreate = "create"
+	// ResourceOutcomeUpdate is counted when a resource was changed to match a new expected configuration
+	ResourceOutcomeUpdate = "update"
+	// ResourceOutcomeDriftRestore is counted when a resource that was changed on the cluster was restored
+	ResourceOutcomeDriftRestore = "drift_restore"
+	// ResourceOutcomeError is counted when a resource could not be reconciled
+	ResourceOutcomeError = "error"
+)
+
+// Reasons a ManagedFleetNotificationRecord item is removed
+const (
+	// RecordItemRemovalReasonStale is counted when an item has not been updated within the stale timeout
+	RecordItemRemovalReasonStale = "stale"
+	// RecordItemRemovalReasonNoLastTransitionTime is counted when an item has no last transition time
+	RecordItemRemovalReasonNoLastTransitionTime = "no_last_transition_time"
+	// RecordItemRemovalReasonNotificationDeleted is counted when the ManagedFleetNotification of an item was deleted
+	RecordItemRemovalReasonNotificationDeleted = "notification_deleted"
 )
 
 var (
@@ -22,9 +52,59 @@ var (
 		Help:      "No OCM Agent resource found",
 	}, []string{})
 
+	MetricOcmAgentPaused = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsTag,
+		Name:      "ocm_agent_paused",
+		Help:      "Reconciliation of the OCM Agent resources is paused",
+	}, []string{nameLabel})
+
+	MetricResourceReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricsTag,
+		Name:      "resource_reconcile_total",
+		Help:      "Outcomes of reconciling the OCM Agent resources",
+	}, []string{nameLabel, kindLabel, outcomeLabel})
+
+	MetricResourceReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: metricsTag,
+		Name:      "resource_reconcile_duration_seconds",
+		Help:      "Time taken to reconcile the OCM Agent resources",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{nameLabel, kindLabel})
+
+	MetricResourceLastReconcileSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsTag,
+		Name:      "resource_last_reconcile_success_timestamp_seconds",
+		Help:      "Time the OCM Agent resources were last reconciled successfully",
+	}, []string{nameLabel, kindLabel})
+
+	MetricFleetNotificationRecordItems = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsTag,
+		Name:      "fleet_notification_record_items",
+		Help:      "Number of ManagedFleetNotificationRecord items per management cluster and notification",
+	}, []string{managementClusterLabel, notificationNameLabel})
+
+	MetricFleetNotificationRecordItemsRemoved = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricsTag,
+		Name:      "fleet_notification_record_items_removed_total",
+		Help:      "Number of ManagedFleetNotificationRecord items removed by the cleanup",
+	}, []string{managementClusterLabel, notificationNameLabel, reasonLabel})
+
+	MetricFleetNotificationRecordOldestItemAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: metricsTag,
+		Name:      "fleet_notification_record_oldest_item_age_seconds",
+		Help:      "Time since the least recently updated ManagedFleetNotificationRecord item per management cluster and notification",
+	}, []string{managementClusterLabel, notificationNameLabel})
+
 	MetricsList = []prometheus.Collector{
 		MetricPullSecretInvalid,
 		MetricOcmAgentResourceAbsent,
+		MetricOcmAgentPaused,
+		MetricResourceReconcileTotal,
+		MetricResourceReconcileDuration,
+		MetricResourceLastReconcileSuccess,
+		MetricFleetNotificationRecordItems,
+		MetricFleetNotificationRecordItemsRemoved,
+		MetricFleetNotificationRecordOldestItemAge,
 	}
 )
 
@@ -38,6 +118,41 @@ func UpdateMetricOcmAgentResourceAbsent() {
 		float64(1))
 }
 
+func UpdateMetricOcmAgentPaused(ocmAgentName string) {
+	MetricOcmAgentPaused.With(prometheus.Labels{
+		nameLabel: ocmAgentName}).Set(float64(1))
+}
+
+func UpdateMetricResourceReconcileTotal(ocmAgentName, kind, outcome string) {
+	MetricResourceReconcileTotal.With(prometheus.Labels{
+		nameLabel: ocmAgentName, kindLabel: kind, outcomeLabel: outcome}).Inc()
+}
+
+func UpdateMetricResourceReconcileDuration(ocmAgentName, kind string, duration time.Duration) {
+	MetricResourceReconcileDuration.With(prometheus.Labels{
+		nameLabel: ocmAgentName, kindLabel: kind}).Observe(duration.Seconds())
+}
+
+func UpdateMetricResourceLastReconcileSuccess(ocmAgentName, kind string, timestamp time.Time) {
+	MetricResourceLastReconcileSuccess.With(prometheus.Labels{
+		nameLabel: ocmAgentName, kindLabel: kind}).Set(float64(timestamp.Unix()))
+}
+
+func UpdateMetricFleetNotificationRecordItems(managementCluster, notificationName string, count int) {
+	MetricFleetNotificationRecordItems.With(prometheus.Labels{
+		managementClusterLabel: managementCluster, notificationNameLabel: notificationName}).Set(float64(count))
+}
+
+func UpdateMetricFleetNotificationRecordItemsRemoved(managementCluster, notificationName, reason string) {
+	MetricFleetNotificationRecordItemsRemoved.With(prometheus.Labels{
+		managementClusterLabel: managementCluster, notificationNameLabel: notificationName, reasonLabel: reason}).Inc()
+}
+
+func UpdateMetricFleetNotificationRecordOldestItemAge(managementCluster, notificationName string, age time.Duration) {
+	MetricFleetNotificationRecordOldestItemAge.With(prometheus.Labels{
+		managementClusterLabel: managementCluster, notificationNameLabel: notificationName}).Set(age.Seconds())
+}
+
 func ResetMetricPullSecretInvalid(ocmAgentName string) {
 	MetricPullSecretInvalid.With(prometheus.Labels{
 		nameLabel: ocmAgentName}).Set(float64(0))
@@ -46,3 +161,32 @@ func ResetMetricPullSecretInvalid(ocmAgentName string) {
 func ResetMetricOcmAgentResourceAbsent() {
 	MetricOcmAgentResourceAbsent.WithLabelValues().Set(float64(0))
 }
+
+func ResetMetricOcmAgentPaused(ocmAgentName string) {
+	MetricOcmAgentPaused.With(prometheus.Labels{
+		nameLabel: ocmAgentName}).Set(float64(0))
+}
+
+// ResetMetricFleetNotificationRecordItems removes the item counts and ages of a management cluster,
+// so that notifications no longer in its record are not reported
+func ResetMetricFleetNotificationRecordItems(managementCluster string) {
+	MetricFleetNotificationRecordItems.DeletePartialMatch(prometheus.Labels{
+		managementClusterLabel: managementCluster})
+	MetricFleetNotificationRecordOldestItemAge.DeletePartialMatch(prometheus.Labels{
+		managementClusterLabel: managementCluster})
+}
+
+// ResetMetricFleetNotificationRecordNotificationItems removes the item count and age of a notification
+// of a management cluster, once the notification was deleted
+func ResetMetricFleetNotificationRecordNotificationItems(managementCluster, notificationName string) {
+	MetricFleetNotificationRecordItems.Delete(prometheus.Labels{
+		managementClusterLabel: managementCluster, notificationNameLabel: notificationName})
+	ResetMetricFleetNotificationRecordOldestItemAge(managementCluster, notificationName)
+}
+
+// ResetMetricFleetNotificationRecordOldestItemAge removes the item age of a notification of a
+// management cluster, once none of its items has been updated
+func ResetMetricFleetNotificationRecordOldestItemAge(managementCluster, notificationName string) {
+	MetricFleetNotificationRecordOldestItemAge.Delete(prometheus.Labels{
+		managementClusterLabel: managementCluster, notificationNameLabel: notificationName})
+}
@@ -1,7 +1,10 @@
 package localmetrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
 
 	. "github.com/onsi/ginkgo"
@@ -20,6 +23,7 @@ var _ = Describe("LocalMetrics", func() {
 		// Reset all metrics before each test
 		ResetMetricPullSecretInvalid(testOcmAgentName)
 		ResetMetricOcmAgentResourceAbsent()
+		ResetMetricOcmAgentPaused(testOcmAgentName)
 	})
 
 	Context("When updating MetricPullSecretInvalid", func() {
@@ -84,6 +88,36 @@ var _ = Describe("LocalMetrics", func() {
 			Expect(resetValue).To(Equal(float64(0)))
 		})
 	})
+
+	Context("When updating MetricOcmAgentPaused", func() {
+		It("should set metric to 1 when the OCM agent is paused and back to 0 when resumed", func() {
+			UpdateMetricOcmAgentPaused(testOcmAgentName)
+			Expect(getGaugeValue(MetricOcmAgentPaused, prometheus.Labels{
+				nameLabel: testOcmAgentName,
+			})).To(Equal(float64(1)))
+
+			ResetMetricOcmAgentPaused(testOcmAgentName)
+			Expect(getGaugeValue(MetricOcmAgentPaused, prometheus.Labels{
+				nameLabel: testOcmAgentName,
+			})).To(Equal(float64(0)))
+		})
+	})
+
+	Context("When updating the resource reconcile metrics", func() {
+		It("should count the outcomes and record the last success by kind", func() {
+			MetricResourceReconcileTotal.Reset()
+			UpdateMetricResourceReconcileTotal(testOcmAgentName, "Deployment", ResourceOutcomeCreate)
+			UpdateMetricResourceReconcileTotal(testOcmAgentName, "Deployment", ResourceOutcomeCreate)
+			Expect(testutil.ToFloat64(MetricResourceReconcileTotal.WithLabelValues(
+				testOcmAgentName, "Deployment", ResourceOutcomeCreate))).To(Equal(float64(2)))
+
+			now := time.Unix(1700000000, 0)
+			UpdateMetricResourceLastReconcileSuccess(testOcmAgentName, "Deployment", now)
+			Expect(getGaugeValue(MetricResourceLastReconcileSuccess, prometheus.Labels{
+				nameLabel: testOcmAgentName, kindLabel: "Deployment",
+			})).To(Equal(float64(now.Unix())))
+		})
+	})
 })
 
 // Helper function to get gauge value with labels
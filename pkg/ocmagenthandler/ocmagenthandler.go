@@ -2,16 +2,26 @@ package ocmagenthandler
 
 import (
 	"context"
+	"errors"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 )
 
+var (
+	// ErrPullSecretInvalid is returned when the OCM access token cannot be sourced from the cluster pull secret
+	ErrPullSecretInvalid = errors.New("cluster pull secret is invalid")
+	// ErrProxyUnavailable is returned when the cluster proxy configuration cannot be retrieved
+	ErrProxyUnavailable = errors.New("cluster proxy configuration is unavailable")
+)
+
 //go:generate mockgen -source $GOFILE -destination ../../pkg/util/test/generated/mocks/$GOPACKAGE/interfaces.go -package mocks
 
 type OcmAgentHandlerBuilder interface {
@@ -19,19 +29,23 @@ type OcmAgentHandlerBuilder interface {
 }
 
 type ocmAgentHandlerBuilder struct {
-	Client client.Client
+	Client   client.Client
+	Recorder events.EventRecorder
 }
 
-func NewBuilder(c client.Client) OcmAgentHandlerBuilder {
-	return &ocmAgentHandlerBuilder{Client: c}
+// NewBuilder returns a builder of OCMAgentHandlers that manage the OCM Agent resources with the
+// supplied client and record events against the OcmAgent with the supplied recorder
+func NewBuilder(c client.Client, recorder events.EventRecorder) OcmAgentHandlerBuilder {
+	return &ocmAgentHandlerBuilder{Client: c, Recorder: recorder}
 }
 
 func (oab *ocmAgentHandlerBuilder) New() (OCMAgentHandler, error) {
 	log := ctrl.Log.WithName("handler").WithName("OCMAgent")
 	oaohandler := &ocmAgentHandler{
-		Client: oab.Client,
-		Log:    log,
-		Scheme: oab.Client.Scheme(),
+		Client:   oab.Client,
+		Log:      log,
+		Scheme:   oab.Client.Scheme(),
+		Recorder: oab.Recorder,
 	}
 	return oaohandler, nil
 }
@@ -45,47 +59,60 @@ type OCMAgentHandler interface {
 
 type ensureResource func(ctx context.Context, agent ocmagentv1alpha1.OcmAgent) error
 
+// ensureStep is an ensure function along with the kind of the resources it manages
+type ensureStep struct {
+	kind   string
+	ensure ensureResource
+}
+
 type ocmAgentHandler struct {
-	Client client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Client   client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
 }
 
 func (o *ocmAgentHandler) EnsureOCMAgentResourcesExist(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) error {
-	var ensureFuncs []ensureResource
 	var secretUpdated bool
-	var err error
 
 	// Ensure secret first and check if it was updated
 	if !ocmAgent.Spec.FleetMode {
-		secretUpdated, err = o.ensureAccessTokenSecret(ctx, ocmAgent)
+		err := observeEnsure(ocmAgent, "Secret", func() error {
+			var err error
+			secretUpdated, err = o.ensureAccessTokenSecret(ctx, ocmAgent)
+			return err
+		})
 		if err != nil {
 			o.Log.Error(err, "Failed to ensure access token secret")
 			return err
 		}
 	} else {
-		err = o.ensureFleetClientSecret(ctx, ocmAgent)
+		err := observeEnsure(ocmAgent, "Secret", func() error {
+			return o.ensureFleetClientSecret(ctx, ocmAgent)
+		})
 		if err != nil {
 			o.Log.Error(err, "Failed to ensure fleet client secret")
 			return err
 		}
 	}
 
-	ensureFuncs = []ensureResource{
-		o.ensureDeployment,
-		o.ensureAllConfigMaps,
-		o.ensureService,
-		o.ensureAllNetworkPolicies,
-		o.ensureServiceMonitor,
+	ensureSteps := []ensureStep{
+		{kind: "Deployment", ensure: o.ensureDeployment},
+		{kind: "ConfigMap", ensure: o.ensureAllConfigMaps},
+		{kind: "Service", ensure: o.ensureService},
+		{kind: "NetworkPolicy", ensure: o.ensureAllNetworkPolicies},
+		{kind: "ServiceMonitor", ensure: o.ensureServiceMonitor},
 	}
 	if ocmAgent.Spec.Replicas > 1 {
-		ensureFuncs = append(ensureFuncs, o.ensurePodDisruptionBudget)
+		ensureSteps = append(ensureSteps, ensureStep{kind: "PodDisruptionBudget", ensure: o.ensurePodDisruptionBudget})
 	}
 
-	for _, fn := range ensureFuncs {
-		err := fn(ctx, ocmAgent)
+	for _, step := range ensureSteps {
+		err := observeEnsure(ocmAgent, step.kind, func() error {
+			return step.ensure(ctx, ocmAgent)
+		})
 		if err != nil {
-			o.Log.Error(err, "Ensure function failed")
+			o.Log.Error(err, "Ensure function failed", "kind", step.kind)
 			return err
 		}
 	}
@@ -95,8 +122,12 @@ func (o *ocmAgentHandler) EnsureOCMAgentResourcesExist(ctx context.Context, ocmA
 		o.Log.Info("Triggering pod restart after secret update", "ocmAgent", ocmAgent.Name)
 		if err := o.restartOCMAgentPods(ctx, ocmAgent); err != nil {
 			o.Log.Error(err, "Unable to trigger pod restart for updated secret")
+			o.Recorder.Eventf(&ocmAgent, nil, corev1.EventTypeWarning, eventReasonPodRestartFailed, eventActionRestart,
+				"Failed to restart the OCM Agent pods after the access token was rotated: %v", err)
 			return err
 		}
+		o.Recorder.Eventf(&ocmAgent, nil, corev1.EventTypeNormal, eventReasonPodsRestarted, eventActionRestart,
+			"Restarted the OCM Agent pods to use the rotated access token")
 	}
 
 	return nil
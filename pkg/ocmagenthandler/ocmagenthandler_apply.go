@@ -0,0 +1,152 @@
+package ocmagenthandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
+)
+
+// applyResult describes what applying a resource did to it on the cluster
+type applyResult string
+
+const (
+	applyResultCreated       applyResult = "created"
+	applyResultUpdated       applyResult = "updated"
+	applyResultDriftRestored applyResult = "driftRestored"
+	applyResultUnchanged     applyResult = "unchanged"
+	applyResultSkipped       applyResult = "skipped"
+)
+
+// resourceApplyConfiguration is the typed apply configuration of an OCM Agent resource, such as
+// the ones of k8s.io/client-go/applyconfigurations. Only the fields set on it are applied.
+type resourceApplyConfiguration interface {
+	GetAPIVersion() *string
+	GetKind() *string
+	GetName() *string
+	GetNamespace() *string
+}
+
+// applyResource server-side applies the supplied apply configuration with the operator's field manager,
+// and returns the applied resource. Only the fields set in the apply configuration are owned by the
+// operator, so fields defaulted by the API server or set by other controllers are left alone, and an
+// apply that changes nothing does not write to the cluster. When an owner is supplied it is set as the
+// controller of the resource. Resources annotated as unmanaged on the cluster are skipped.
+// The hash of the applied configuration is recorded in an annotation, so that an apply that changed
+// a resource without its expected configuration changing can be told apart as restoring drift.
+func (o *ocmAgentHandler) applyResource(ctx context.Context, owner *ocmagentv1alpha1.OcmAgent, ac resourceApplyConfiguration) (client.Object, applyResult, error) {
+	// The apply configuration only holds the fields the operator sets, so it converts to an unstructured
+	// apply configuration without any zero value. Unlike a typed apply configuration, the unstructured one
+	// is updated from the response, which tells whether the apply changed the resource.
+	data, err := json.Marshal(ac)
+	if err != nil {
+		return nil, "", err
+	}
+	applyConfig := &unstructured.Unstructured{}
+	if err := applyConfig.UnmarshalJSON(data); err != nil {
+		return nil, "", err
+	}
+	if owner != nil {
+		if err := controllerutil.SetControllerReference(owner, applyConfig, o.Scheme); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Record the current version of the resource to tell whether the apply changed it
+	current := &metav1.PartialObjectMetadata{}
+	current.SetGroupVersionKind(applyConfig.GroupVersionKind())
+	if err := o.Client.Get(ctx, client.ObjectKeyFromObject(applyConfig), current); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, "", err
+		}
+	}
+	if isUnmanaged(current) {
+		return applyConfig, applyResultSkipped, nil
+	}
+	if err := o.upgradeManagedFields(ctx, current); err != nil {
+		return nil, "", err
+	}
+
+	configHash, err := hashConfig(applyConfig.Object)
+	if err != nil {
+		return nil, "", err
+	}
+	annotations := applyConfig.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[oah.ConfigHashAnnotation] = configHash
+	applyConfig.SetAnnotations(annotations)
+
+	if err := o.Client.Apply(ctx, client.ApplyConfigurationFromUnstructured(applyConfig),
+		client.FieldOwner(oah.FieldManager), client.ForceOwnership); err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case current.ResourceVersion == "":
+		return applyConfig, applyResultCreated, nil
+	case applyConfig.GetResourceVersion() == current.ResourceVersion:
+		return applyConfig, applyResultUnchanged, nil
+	case current.GetAnnotations()[oah.ConfigHashAnnotation] != configHash:
+		return applyConfig, applyResultUpdated, nil
+	default:
+		return applyConfig, applyResultDriftRestored, nil
+	}
+}
+
+// upgradeManagedFields moves the fields of a resource that were created or updated by earlier versions
+// of the operator from their update manager to the operator's field manager. Otherwise, the fields the
+// operator no longer sets would be left on the resource rather than be removed by the next apply.
+// This only patches the resource the first time it is applied.
+func (o *ocmAgentHandler) upgradeManagedFields(ctx context.Context, current *metav1.PartialObjectMetadata) error {
+	if current.ResourceVersion == "" {
+		return nil
+	}
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(current, sets.New(oah.LegacyFieldManager), oah.FieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+	return o.Client.Patch(ctx, current, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// hashConfig returns a hash of the configuration of a resource
+func hashConfig(content map[string]interface{}) (string, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(data)
+	return fmt.Sprintf("%016x", hasher.Sum64()), nil
+}
+
+// isUnmanaged reports whether the operator has been asked to leave a resource alone with the unmanaged annotation
+func isUnmanaged(obj metav1.Object) bool {
+	return obj.GetAnnotations()[ocmagentv1alpha1.UnmanagedAnnotation] == "true"
+}
+
+// applyConfigurationFrom converts typed API fields to their apply configuration. The typed fields
+// must not serialize implicit zero values, such as the ones set in the OcmAgent spec, so that the
+// apply configuration holds exactly the fields that are set.
+func applyConfigurationFrom[T any](fields any) (T, error) {
+	var ac T
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return ac, err
+	}
+	err = json.Unmarshal(data, &ac)
+	return ac, err
+}
@@ -0,0 +1,253 @@
+package ocmagenthandler
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/mock/gomock"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
+	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// expectGetResourceVersion expects the current version of a resource to be looked up before it
+// is applied. An empty version means the resource does not exist.
+func expectGetResourceVersion(mockClient *clientmocks.MockClient, key types.NamespacedName, version string) *gomock.Call {
+	call := mockClient.EXPECT().Get(gomock.Any(), key, gomock.AssignableToTypeOf(&metav1.PartialObjectMetadata{})).Times(1)
+	if version == "" {
+		return call.Return(k8serrs.NewNotFound(schema.GroupResource{}, key.Name))
+	}
+	return call.SetArg(2, metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{ResourceVersion: version}})
+}
+
+// expectApply expects a resource to be applied with the operator's field manager, decodes the
+// applied resource into the supplied object when set, and returns the supplied resource version
+func expectApply(mockClient *clientmocks.MockClient, version string, into client.Object) *gomock.Call {
+	return mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), client.FieldOwner(oah.FieldManager), client.ForceOwnership).Times(1).DoAndReturn(
+		func(ctx context.Context, ac runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+			if into != nil {
+				content := ac.(runtime.Unstructured).UnstructuredContent()
+				Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(content, into)).To(Succeed())
+			}
+			ac.(metav1.Object).SetResourceVersion(version)
+			return nil
+		})
+}
+
+// fromApplyConfiguration decodes an apply configuration into the resource it configures
+func fromApplyConfiguration[T any](ac any) T {
+	obj, err := applyConfigurationFrom[T](ac)
+	Expect(err).To(BeNil())
+	return obj
+}
+
+var _ = Describe("OCM Agent Apply Handler", func() {
+	var (
+		mockClient *clientmocks.MockClient
+		mockCtrl   *gomock.Controller
+
+		testOcmAgent        ocmagentv1alpha1.OcmAgent
+		testOcmAgentHandler ocmAgentHandler
+		testConfigMap       *corev1ac.ConfigMapApplyConfiguration
+		testNamespacedName  types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		testOcmAgent = testconst.TestOCMAgent
+		testOcmAgentHandler = ocmAgentHandler{
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
+		}
+		testConfigMap = buildOCMAgentConfigMap(testOcmAgent, "cluster-id")
+		testNamespacedName = types.NamespacedName{Namespace: *testConfigMap.Namespace, Name: *testConfigMap.Name}
+	})
+
+	Context("Applying a resource", func() {
+		When("the resource does not exist", func() {
+			It("reports it as created", func() {
+				gomock.InOrder(
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", nil),
+				)
+				_, result, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+				Expect(result).To(Equal(applyResultCreated))
+			})
+		})
+		When("the apply changes the resource to a new configuration", func() {
+			It("reports it as updated", func() {
+				gomock.InOrder(
+					expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+					expectApply(mockClient, "2", nil),
+				)
+				_, result, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+				Expect(result).To(Equal(applyResultUpdated))
+			})
+		})
+		When("the apply restores a resource that was changed on the cluster", func() {
+			It("reports it as drift restored", func() {
+				appliedConfigMap := &corev1.ConfigMap{}
+				gomock.InOrder(
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", appliedConfigMap),
+				)
+				_, _, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+				Expect(appliedConfigMap.Annotations).To(HaveKey(oah.ConfigHashAnnotation))
+
+				// The configuration last applied is recorded on the resource, so a change must have come from elsewhere
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, metav1.PartialObjectMetadata{
+						ObjectMeta: metav1.ObjectMeta{ResourceVersion: "2", Annotations: appliedConfigMap.Annotations},
+					}),
+					expectApply(mockClient, "3", nil),
+				)
+				_, result, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+				Expect(result).To(Equal(applyResultDriftRestored))
+			})
+		})
+		When("the apply does not change the resource", func() {
+			It("reports it as unchanged", func() {
+				gomock.InOrder(
+					expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+					expectApply(mockClient, "1", nil),
+				)
+				_, result, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+				Expect(result).To(Equal(applyResultUnchanged))
+			})
+		})
+		When("the resource is annotated as unmanaged", func() {
+			It("skips the apply", func() {
+				mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, metav1.PartialObjectMetadata{
+					ObjectMeta: metav1.ObjectMeta{
+						ResourceVersion: "1",
+						Annotations:     map[string]string{ocmagentv1alpha1.UnmanagedAnnotation: "true"},
+					},
+				})
+				mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				_, result, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+				Expect(result).To(Equal(applyResultSkipped))
+			})
+		})
+		It("applies only the fields that are set, with the owner as controller", func() {
+			gomock.InOrder(
+				expectGetResourceVersion(mockClient, testNamespacedName, ""),
+				mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, ac runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						content := ac.(runtime.Unstructured).UnstructuredContent()
+						Expect(content).To(HaveKeyWithValue("apiVersion", "v1"))
+						Expect(content).To(HaveKeyWithValue("kind", "ConfigMap"))
+						Expect(content["metadata"]).NotTo(HaveKey("creationTimestamp"))
+						applied := &corev1.ConfigMap{}
+						Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(content, applied)).To(Succeed())
+						Expect(applied.Data).To(Equal(testConfigMap.Data))
+						Expect(applied.OwnerReferences).To(HaveLen(1))
+						Expect(applied.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
+						Expect(*applied.OwnerReferences[0].Controller).To(BeTrue())
+						return nil
+					}),
+			)
+			_, _, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+			Expect(err).To(BeNil())
+		})
+		It("does not set a controller without an owner", func() {
+			appliedConfigMap := &corev1.ConfigMap{}
+			gomock.InOrder(
+				expectGetResourceVersion(mockClient, testNamespacedName, ""),
+				expectApply(mockClient, "1", appliedConfigMap),
+			)
+			_, _, err := testOcmAgentHandler.applyResource(testconst.Context, nil, testConfigMap)
+			Expect(err).To(BeNil())
+			Expect(appliedConfigMap.OwnerReferences).To(BeEmpty())
+		})
+		When("the resource cannot be retrieved", func() {
+			It("returns the error without applying", func() {
+				mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Return(fmt.Errorf("fake error"))
+				_, _, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).NotTo(BeNil())
+			})
+		})
+		When("the apply fails", func() {
+			It("returns the error", func() {
+				gomock.InOrder(
+					expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+					mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("fake error")),
+				)
+				_, _, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Context("Upgrading the managed fields of a resource", func() {
+		var legacyManagedFields []metav1.ManagedFieldsEntry
+		BeforeEach(func() {
+			legacyManagedFields = []metav1.ManagedFieldsEntry{{
+				Manager:    oah.LegacyFieldManager,
+				Operation:  metav1.ManagedFieldsOperationUpdate,
+				APIVersion: "v1",
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:stale":{}}}`)},
+			}}
+		})
+		When("the resource was last updated by an earlier version of the operator", func() {
+			It("moves the fields to the operator's field manager before applying", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, metav1.PartialObjectMetadata{
+						ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1", ManagedFields: legacyManagedFields},
+					}),
+					mockClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+							Expect(patch.Type()).To(Equal(types.JSONPatchType))
+							data, err := patch.Data(obj)
+							Expect(err).To(BeNil())
+							Expect(string(data)).To(ContainSubstring(`"manager":"` + oah.FieldManager + `"`))
+							Expect(string(data)).To(ContainSubstring(`"operation":"Apply"`))
+							return nil
+						}),
+					expectApply(mockClient, "2", nil),
+				)
+				_, _, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+			})
+		})
+		When("the resource is already applied by the operator", func() {
+			It("does not patch the managed fields", func() {
+				legacyManagedFields[0].Manager = oah.FieldManager
+				legacyManagedFields[0].Operation = metav1.ManagedFieldsOperationApply
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, metav1.PartialObjectMetadata{
+						ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1", ManagedFields: legacyManagedFields},
+					}),
+					expectApply(mockClient, "1", nil),
+				)
+				mockClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				_, _, err := testOcmAgentHandler.applyResource(testconst.Context, &testOcmAgent, testConfigMap)
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -2,15 +2,12 @@ package ocmagenthandler
 
 import (
 	"context"
-	"fmt"
-	"reflect"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 
 	configv1 "github.com/openshift/api/config/v1"
 
@@ -19,7 +16,7 @@ import (
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 )
 
-func buildOCMAgentConfigMap(ocmAgent ocmagentv1alpha1.OcmAgent, clusterId string) *corev1.ConfigMap {
+func buildOCMAgentConfigMap(ocmAgent ocmagentv1alpha1.OcmAgent, clusterId string) *corev1ac.ConfigMapApplyConfiguration {
 
 	// We are ensuring to keep the configmap name always unique from secret name so adding a suffix
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name + oah.ConfigMapSuffix)
@@ -33,46 +30,31 @@ func buildOCMAgentConfigMap(ocmAgent ocmagentv1alpha1.OcmAgent, clusterId string
 		CMData[oah.OCMAgentConfigClusterID] = clusterId
 	}
 
-	cm := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-		},
-		Data: CMData,
-	}
+	cm := corev1ac.ConfigMap(namespacedName.Name, namespacedName.Namespace).
+		WithData(CMData)
 
 	return cm
 }
 
-func buildTrustedCaConfigMap() *corev1.ConfigMap {
+func buildTrustedCaConfigMap() *corev1ac.ConfigMapApplyConfiguration {
 	namespacedName := oah.BuildNamespacedName(oah.TrustedCaBundleConfigMapName)
 	labels := map[string]string{
 		oah.InjectCaBundleIndicator: "true",
 	}
-	cm := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-			Labels:    labels,
-		},
-	}
+	cm := corev1ac.ConfigMap(namespacedName.Name, namespacedName.Namespace).
+		WithLabels(labels)
 	return cm
 }
 
-func buildCAMOConfigMap(ocmAgent ocmagentv1alpha1.OcmAgent) (*corev1.ConfigMap, error) {
+func buildCAMOConfigMap(ocmAgent ocmagentv1alpha1.OcmAgent) (*corev1ac.ConfigMapApplyConfiguration, error) {
 	oaServiceURL, err := oah.BuildServiceURL(ocmAgent.Name, ocmAgent.Namespace)
 	if err != nil {
 		return nil, err
 	}
-	camoCM := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      oah.CAMOConfigMapNamespacedName.Name,
-			Namespace: oah.CAMOConfigMapNamespacedName.Namespace,
-		},
-		Data: map[string]string{
+	camoCM := corev1ac.ConfigMap(oah.CAMOConfigMapNamespacedName.Name, oah.CAMOConfigMapNamespacedName.Namespace).
+		WithData(map[string]string{
 			oah.OCMAgentServiceURLKey: oaServiceURL,
-		},
-	}
+		})
 	return camoCM, nil
 }
 
@@ -89,7 +71,7 @@ func (o *ocmAgentHandler) ensureAllConfigMaps(ctx context.Context, ocmAgent ocma
 	}
 	clusterID := string(cv.Spec.ClusterID)
 
-	var oaCM *corev1.ConfigMap
+	var oaCM *corev1ac.ConfigMapApplyConfiguration
 	if ocmAgent.Spec.FleetMode {
 		oaCM = buildOCMAgentConfigMap(ocmAgent, "")
 	} else {
@@ -123,56 +105,24 @@ func (o *ocmAgentHandler) ensureAllConfigMaps(ctx context.Context, ocmAgent ocma
 	return nil
 }
 
-// ensureConfigMaps ensures that the OCM Agent Operator-managed configmap
+// ensureConfigMap ensures that the OCM Agent Operator-managed configmap
 // exists on the cluster and that the configuration matches what is expected.
 // And apply the ownerReference to the configmaps if needed
-func (o *ocmAgentHandler) ensureConfigMap(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent, cm *corev1.ConfigMap, manager bool) error {
-
-	foundResource := &corev1.ConfigMap{}
-	namespacedName := types.NamespacedName{
-		Namespace: cm.Namespace,
-		Name:      cm.Name,
+func (o *ocmAgentHandler) ensureConfigMap(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent, cm *corev1ac.ConfigMapApplyConfiguration, manager bool) error {
+	var owner *ocmagentv1alpha1.OcmAgent
+	if manager {
+		owner = &ocmAgent
 	}
 
-	// Does the resource already exist?
-	o.Log.Info("ensuring configmap exists", "resource", namespacedName.String())
-	if err := o.Client.Get(ctx, namespacedName, foundResource); err != nil {
-		if k8serrors.IsNotFound(err) {
-			// It does not exist, so must be created.
-			o.Log.Info(fmt.Sprintf("configmap does not exist, %s/%s; will be created.",
-				cm.Namespace,
-				cm.Name))
-			// Set the controller reference if needed
-			if manager {
-				if err := controllerutil.SetControllerReference(&ocmAgent, cm, o.Scheme); err != nil {
-					return err
-				}
-			}
-			// and create it
-			err = o.Client.Create(ctx, cm)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Return unexpectedly
-			return err
-		}
-	} else {
-		// skip update the configmap for trusted-ca-bundle to avoid the race with CNO
-		if cm.Name != oah.TrustedCaBundleConfigMapName {
-			// It does exist, check if it is what we expected
-			if !reflect.DeepEqual(foundResource.Data, cm.Data) {
-				// Update only the Data field to preserve server-managed metadata
-				o.Log.Info(fmt.Sprintf("configmap exists but contains unexpected configuration, %s/%s. Restoring.",
-					cm.Namespace, cm.Name))
-				foundResource.Data = cm.Data
-				if err = o.Client.Update(ctx, foundResource); err != nil {
-					o.Log.Error(err, "Failed to update configmap")
-					return err
-				}
-			}
-		}
+	// The trusted-ca-bundle data is injected by the CNO. As it is not set here, it is not
+	// owned by the operator and applying the configmap does not race with the CNO.
+	o.Log.Info("ensuring configmap exists", "resource", types.NamespacedName{Namespace: *cm.Namespace, Name: *cm.Name}.String())
+	applied, result, err := o.applyResource(ctx, owner, cm)
+	if err != nil {
+		o.Log.Error(err, "Failed to apply configmap")
+		return err
 	}
+	o.recordApplyEvent(&ocmAgent, applied, result)
 	return nil
 }
 
@@ -184,7 +134,7 @@ func (o *ocmAgentHandler) ensureAllConfigMapsDeleted(ctx context.Context, ocmAge
 	}
 
 	for _, cm := range cmsToDelete {
-		err := o.ensureConfigMapDeleted(ctx, cm)
+		err := o.ensureConfigMapDeleted(ctx, ocmAgent, cm)
 		if err != nil {
 			return err
 		}
@@ -193,7 +143,7 @@ func (o *ocmAgentHandler) ensureAllConfigMapsDeleted(ctx context.Context, ocmAge
 	return nil
 }
 
-func (o *ocmAgentHandler) ensureConfigMapDeleted(ctx context.Context, n types.NamespacedName) error {
+func (o *ocmAgentHandler) ensureConfigMapDeleted(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent, n types.NamespacedName) error {
 	foundResource := &corev1.ConfigMap{}
 	o.Log.Info("ensuring configmap removed", "resource", n.String())
 	// Does the resource already exist?
@@ -207,7 +157,7 @@ func (o *ocmAgentHandler) ensureConfigMapDeleted(ctx context.Context, n types.Na
 		}
 	}
 	// It does, so remove it
-	err := o.Client.Delete(ctx, foundResource)
+	err := o.deleteResource(ctx, &ocmAgent, foundResource)
 	if err != nil {
 		return err
 	}
@@ -216,7 +166,7 @@ func (o *ocmAgentHandler) ensureConfigMapDeleted(ctx context.Context, n types.Na
 
 func (o *ocmAgentHandler) fetchClusterVersion(ctx context.Context) (*configv1.ClusterVersion, error) {
 	cv := &configv1.ClusterVersion{}
-	err := o.Client.Get(ctx, types.NamespacedName{Name: "version"}, cv)
+	err := o.Client.Get(ctx, oah.ClusterVersionNamespacedName, cv)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,6 @@
 package ocmagenthandler
 
 import (
-	"context"
 	"errors"
 	"reflect"
 
@@ -13,7 +12,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/tools/events"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oahconst "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
@@ -39,9 +39,10 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 		mockClient = clientmocks.NewMockClient(mockCtrl)
 		testOcmAgent = testconst.TestOCMAgent
 		testOcmAgentHandler = ocmAgentHandler{
-			Client: mockClient,
-			Log:    testconst.Logger,
-			Scheme: testconst.Scheme,
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
 		}
 		testClusterId = "9345c78b-b6b6-4f42-b242-79bfcc403b0a"
 	})
@@ -49,7 +50,7 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 	Context("When building an OCM Agent ConfigMap ", func() {
 		It("Sets correct name and data with cluster ID", func() {
 			cm := buildOCMAgentConfigMap(testOcmAgent, testClusterId)
-			Expect(cm.Name).To(Equal(testOcmAgent.Name + testconst.TestConfigMapSuffix))
+			Expect(*cm.Name).To(Equal(testOcmAgent.Name + testconst.TestConfigMapSuffix))
 			Expect(cm.Data).To(HaveKeyWithValue(oahconst.OCMAgentConfigClusterID, testClusterId))
 			Expect(cm.Data).To(HaveKeyWithValue(oahconst.OCMAgentConfigURLKey, testOcmAgent.Spec.AgentConfig.OcmBaseUrl))
 			Expect(cm.Data).To(HaveKey(oahconst.OCMAgentConfigServicesKey))
@@ -62,7 +63,7 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 	})
 
 	Context("Managing the OCM Agent ConfigMap", func() {
-		var testConfigMap *corev1.ConfigMap
+		var testConfigMap *corev1ac.ConfigMapApplyConfiguration
 		var testNamespacedName types.NamespacedName
 		BeforeEach(func() {
 			testNamespacedName = oahconst.BuildNamespacedName(testOcmAgent.Name)
@@ -71,36 +72,31 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 		})
 		When("the OCM Agent config already exists", func() {
 			When("the config differs from what is expected", func() {
-				BeforeEach(func() {
-					testConfigMap.Data = map[string]string{"fake": "fake"}
-				})
-				It("updates the configmap and handles update failures", func() {
-					goldenConfig := buildOCMAgentConfigMap(testOcmAgent, testClusterId)
+				It("applies the configmap and handles apply failures", func() {
+					appliedConfigMap := &corev1.ConfigMap{}
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, *testConfigMap),
-						mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-							func(ctx context.Context, d *corev1.ConfigMap, opts ...client.UpdateOptions) error {
-								Expect(d.Data).To(Equal(goldenConfig.Data))
-								return nil
-							}),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "2", appliedConfigMap),
 					)
-					err := testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, goldenConfig, true)
+					err := testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testConfigMap, true)
 					Expect(err).To(BeNil())
+					Expect(appliedConfigMap.Data).To(Equal(testConfigMap.Data))
 
-					// Test update failure
-					testError := errors.New("update failed")
+					// Test apply failure
+					testError := errors.New("apply failed")
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, *testConfigMap),
-						mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(testError),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(testError),
 					)
-					err = testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, goldenConfig, true)
+					err = testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testConfigMap, true)
 					Expect(err).To(Equal(testError))
 				})
 			})
 			When("the configmap matches what is expected", func() {
-				It("does not update the configmap", func() {
+				It("does not fail when the apply changes nothing", func() {
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, *testConfigMap),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
 					)
 					err := testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testConfigMap, true)
 					Expect(err).To(BeNil())
@@ -109,29 +105,17 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 
 		})
 		When("the OCM Agent configmap does not already exist", func() {
-			It("creates the configmap and handles create/get failures", func() {
-				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testConfigMap.Name)
+			It("creates the configmap and handles get failures", func() {
+				appliedConfigMap := &corev1.ConfigMap{}
 
 				// Test successful creation
 				gomock.InOrder(
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Return(notFound),
-					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
-						func(ctx context.Context, d *corev1.ConfigMap, opts ...client.CreateOptions) error {
-							Expect(reflect.DeepEqual(d.Data, testConfigMap.Data)).To(BeTrue())
-							return nil
-						}),
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", appliedConfigMap),
 				)
 				err := testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testConfigMap, true)
 				Expect(err).To(BeNil())
-
-				// Test create failure
-				createError := errors.New("create failed")
-				gomock.InOrder(
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Return(notFound),
-					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(createError),
-				)
-				err = testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testConfigMap, true)
-				Expect(err).To(Equal(createError))
+				Expect(reflect.DeepEqual(appliedConfigMap.Data, testConfigMap.Data)).To(BeTrue())
 
 				// Test unexpected get error
 				getError := errors.New("get failed")
@@ -143,32 +127,33 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 		When("removing configmaps", func() {
 			It("handles deletion scenarios and errors", func() {
 				// Test: configmap already removed
-				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testConfigMap.Name)
+				foundConfigMap := fromApplyConfiguration[*corev1.ConfigMap](testConfigMap)
+				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testNamespacedName.Name)
 				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(notFound)
-				err := testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testNamespacedName)
+				err := testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testOcmAgent, testNamespacedName)
 				Expect(err).To(BeNil())
 
 				// Test: successful deletion
 				gomock.InOrder(
-					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *testConfigMap),
-					mockClient.EXPECT().Delete(gomock.Any(), testConfigMap).Return(nil),
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *foundConfigMap),
+					mockClient.EXPECT().Delete(gomock.Any(), foundConfigMap).Return(nil),
 				)
-				err = testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testNamespacedName)
+				err = testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testOcmAgent, testNamespacedName)
 				Expect(err).To(BeNil())
 
 				// Test: delete failure
 				deleteError := errors.New("delete failed")
 				gomock.InOrder(
-					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *testConfigMap),
-					mockClient.EXPECT().Delete(gomock.Any(), testConfigMap).Return(deleteError),
+					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, *foundConfigMap),
+					mockClient.EXPECT().Delete(gomock.Any(), foundConfigMap).Return(deleteError),
 				)
-				err = testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testNamespacedName)
+				err = testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testOcmAgent, testNamespacedName)
 				Expect(err).To(Equal(deleteError))
 
 				// Test: get error during deletion
 				getError := errors.New("get failed during delete")
 				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(getError)
-				err = testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testNamespacedName)
+				err = testOcmAgentHandler.ensureConfigMapDeleted(testconst.Context, testOcmAgent, testNamespacedName)
 				Expect(err).To(Equal(getError))
 			})
 		})
@@ -179,8 +164,8 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 			// Test successful build
 			cm, err := buildCAMOConfigMap(testOcmAgent)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cm.Name).To(Equal(oahconst.CAMOConfigMapNamespacedName.Name))
-			Expect(cm.Namespace).To(Equal(oahconst.CAMOConfigMapNamespacedName.Namespace))
+			Expect(*cm.Name).To(Equal(oahconst.CAMOConfigMapNamespacedName.Name))
+			Expect(*cm.Namespace).To(Equal(oahconst.CAMOConfigMapNamespacedName.Namespace))
 			Expect(cm.Data).To(HaveKey(oahconst.OCMAgentServiceURLKey))
 
 			// Test URL build failure
@@ -194,58 +179,57 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 	})
 
 	Context("Managing the Trusted CA configmap", func() {
-		It("builds successfully and skips updates", func() {
+		It("builds successfully and only applies its labels", func() {
 			testcm := buildTrustedCaConfigMap()
-			Expect(testcm.Name).To(Equal("trusted-ca-bundle"))
-			Expect(testcm.Namespace).To(Equal(oahconst.OCMAgentNamespace))
-			Expect(testcm.ObjectMeta.Labels).Should(HaveKey(oahconst.InjectCaBundleIndicator))
-
-			// Test that trusted CA bundle updates are skipped
-			testcm.Data = map[string]string{"aaa": "bbb"}
-			testNamespacedName := oahconst.BuildNamespacedName(testcm.Name)
-			mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, *testcm)
+			Expect(*testcm.Name).To(Equal("trusted-ca-bundle"))
+			Expect(*testcm.Namespace).To(Equal(oahconst.OCMAgentNamespace))
+			Expect(testcm.Labels).Should(HaveKey(oahconst.InjectCaBundleIndicator))
+
+			// The injected data must not be owned by the operator
+			appliedConfigMap := &corev1.ConfigMap{}
+			testNamespacedName := oahconst.BuildNamespacedName(*testcm.Name)
+			gomock.InOrder(
+				expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+				expectApply(mockClient, "1", appliedConfigMap),
+			)
 			err := testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testcm, true)
 			Expect(err).To(BeNil())
+			Expect(appliedConfigMap.Labels).Should(HaveKey(oahconst.InjectCaBundleIndicator))
+			Expect(appliedConfigMap.Data).To(BeNil())
 		})
 	})
 
 	Context("When applying a controller reference", func() {
-		var testConfigMap *corev1.ConfigMap
+		var testConfigMap *corev1ac.ConfigMapApplyConfiguration
 		var testNamespacedName types.NamespacedName
-		var notFound *k8serrs.StatusError
 
 		BeforeEach(func() {
 			testNamespacedName = oahconst.BuildNamespacedName(testOcmAgent.Name)
 			testNamespacedName.Name = testNamespacedName.Name + testconst.TestConfigMapSuffix
 			testConfigMap = buildOCMAgentConfigMap(testOcmAgent, testClusterId)
-			notFound = k8serrs.NewNotFound(schema.GroupResource{}, testConfigMap.Name)
 		})
 		It("Adds one if requested", func() {
+			appliedConfigMap := &corev1.ConfigMap{}
 			gomock.InOrder(
-				mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Return(notFound),
-				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
-					func(ctx context.Context, d *corev1.ConfigMap, opts ...client.CreateOptions) error {
-						Expect(d.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
-						Expect(*d.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
-						Expect(*d.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
-						return nil
-					}),
+				expectGetResourceVersion(mockClient, testNamespacedName, ""),
+				expectApply(mockClient, "1", appliedConfigMap),
 			)
 			err := testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testConfigMap, true)
 			Expect(err).To(BeNil())
+			Expect(appliedConfigMap.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
+			Expect(*appliedConfigMap.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
+			Expect(*appliedConfigMap.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
 		})
 
 		It("Does not add one if not requested", func() {
+			appliedConfigMap := &corev1.ConfigMap{}
 			gomock.InOrder(
-				mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Return(notFound),
-				mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
-					func(ctx context.Context, d *corev1.ConfigMap, opts ...client.CreateOptions) error {
-						Expect(d.ObjectMeta.OwnerReferences).To(BeNil())
-						return nil
-					}),
+				expectGetResourceVersion(mockClient, testNamespacedName, ""),
+				expectApply(mockClient, "1", appliedConfigMap),
 			)
 			err := testOcmAgentHandler.ensureConfigMap(testconst.Context, testOcmAgent, testConfigMap, false)
 			Expect(err).To(BeNil())
+			Expect(appliedConfigMap.ObjectMeta.OwnerReferences).To(BeNil())
 		})
 
 	})
@@ -258,7 +242,7 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 			}
 
 			// Test successful fetch
-			mockClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "version"}, gomock.Any()).
+			mockClient.EXPECT().Get(gomock.Any(), oahconst.ClusterVersionNamespacedName, gomock.Any()).
 				SetArg(2, *testClusterVersion).Return(nil)
 			clusterVersion, err := testOcmAgentHandler.fetchClusterVersion(testconst.Context)
 			Expect(err).ToNot(HaveOccurred())
@@ -266,7 +250,7 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 
 			// Test fetch failure
 			testError := errors.New("cluster version not found")
-			mockClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "version"}, gomock.Any()).Return(testError)
+			mockClient.EXPECT().Get(gomock.Any(), oahconst.ClusterVersionNamespacedName, gomock.Any()).Return(testError)
 			clusterVersion, err = testOcmAgentHandler.fetchClusterVersion(testconst.Context)
 			Expect(err).To(Equal(testError))
 			Expect(clusterVersion).To(BeNil())
@@ -280,15 +264,15 @@ var _ = Describe("OCM Agent ConfigMap Handler", func() {
 
 			// Test fleet mode (no CAMO, no cluster ID)
 			testOcmAgent.Spec.FleetMode = true
-			mockClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "version"}, gomock.Any()).SetArg(2, *testClusterVersion)
+			mockClient.EXPECT().Get(gomock.Any(), oahconst.ClusterVersionNamespacedName, gomock.Any()).SetArg(2, *testClusterVersion)
 			mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(k8serrs.NewNotFound(schema.GroupResource{}, "")).Times(2)
-			mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
 			err := testOcmAgentHandler.ensureAllConfigMaps(testconst.Context, testOcmAgent)
 			Expect(err).ToNot(HaveOccurred())
 
 			// Test cluster version fetch error
 			fetchError := errors.New("fetch failed")
-			mockClient.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "version"}, gomock.Any()).Return(fetchError)
+			mockClient.EXPECT().Get(gomock.Any(), oahconst.ClusterVersionNamespacedName, gomock.Any()).Return(fetchError)
 			err = testOcmAgentHandler.ensureAllConfigMaps(testconst.Context, testOcmAgent)
 			Expect(err).To(Equal(fetchError))
 		})
@@ -4,12 +4,9 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
-	"reflect"
 	"sort"
 	"time"
 
-	"github.com/go-logr/logr"
-
 	oconfigv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -17,20 +14,21 @@ import (
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 )
 
-func buildOCMAgentDeployment(ocmAgent ocmagentv1alpha1.OcmAgent) appsv1.Deployment {
+// buildOCMAgentDeployment returns the OCM Agent deployment, with the environment built by buildEnvVars
+func buildOCMAgentDeployment(ocmAgent ocmagentv1alpha1.OcmAgent, env []corev1.EnvVar) (*appsv1ac.DeploymentApplyConfiguration, error) {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name)
 	labels := map[string]string{
 		"app": ocmAgent.Name,
 	}
-	labelSelectors := metav1.LabelSelector{
-		MatchLabels: labels,
-	}
 
 	// Define a volumes for the config
 	tokenSecretVolumeName := ocmAgent.Spec.TokenSecret
@@ -40,181 +38,229 @@ func buildOCMAgentDeployment(ocmAgent ocmagentv1alpha1.OcmAgent) appsv1.Deployme
 	var configVolumeSourceDefaultMode int32 = 0644
 	var trustedCaVolumeSourceDefaultMode int32 = 0644
 
-	volumes := []corev1.Volume{
-		{
-			Name: tokenSecretVolumeName,
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName:  ocmAgent.Spec.TokenSecret,
-					DefaultMode: &secretVolumeSourceDefaultMode,
-				},
-			},
-		},
-		{
-			Name: configVolumeName,
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: ocmAgent.Name + oah.ConfigMapSuffix,
-					},
-					DefaultMode: &configVolumeSourceDefaultMode,
-				},
-			},
-		},
-		{
-			Name: trustedCaVolumeName,
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: oah.TrustedCaBundleConfigMapName,
-					},
-					Items: []corev1.KeyToPath{
-						{
-							Key:  "ca-bundle.crt",
-							Path: "tls-ca-bundle.pem",
-						},
-					},
-					DefaultMode: &trustedCaVolumeSourceDefaultMode,
-				},
-			},
-		},
+	volumes := []*corev1ac.VolumeApplyConfiguration{
+		corev1ac.Volume().
+			WithName(tokenSecretVolumeName).
+			WithSecret(corev1ac.SecretVolumeSource().
+				WithSecretName(ocmAgent.Spec.TokenSecret).
+				WithDefaultMode(secretVolumeSourceDefaultMode)),
+		corev1ac.Volume().
+			WithName(configVolumeName).
+			WithConfigMap(corev1ac.ConfigMapVolumeSource().
+				WithName(ocmAgent.Name + oah.ConfigMapSuffix).
+				WithDefaultMode(configVolumeSourceDefaultMode)),
+		corev1ac.Volume().
+			WithName(trustedCaVolumeName).
+			WithConfigMap(corev1ac.ConfigMapVolumeSource().
+				WithName(oah.TrustedCaBundleConfigMapName).
+				WithItems(corev1ac.KeyToPath().
+					WithKey("ca-bundle.crt").
+					WithPath("tls-ca-bundle.pem")).
+				WithDefaultMode(trustedCaVolumeSourceDefaultMode)),
 	}
 
 	// define the volume mounts for the deployment
-	volumeMounts := []corev1.VolumeMount{
-		{
-			Name:      tokenSecretVolumeName,
-			MountPath: filepath.Join(oah.OCMAgentSecretMountPath, tokenSecretVolumeName),
-		},
-		{
-			Name:      configVolumeName,
-			MountPath: filepath.Join(oah.OCMAgentConfigMountPath, configVolumeName),
-		},
-		{
-			Name:      trustedCaVolumeName,
-			MountPath: "/etc/pki/ca-trust/extracted/pem",
-			ReadOnly:  true,
-		},
+	volumeMounts := []*corev1ac.VolumeMountApplyConfiguration{
+		corev1ac.VolumeMount().
+			WithName(tokenSecretVolumeName).
+			WithMountPath(filepath.Join(oah.OCMAgentSecretMountPath, tokenSecretVolumeName)),
+		corev1ac.VolumeMount().
+			WithName(configVolumeName).
+			WithMountPath(filepath.Join(oah.OCMAgentConfigMountPath, configVolumeName)),
+		corev1ac.VolumeMount().
+			WithName(trustedCaVolumeName).
+			WithMountPath("/etc/pki/ca-trust/extracted/pem").
+			WithReadOnly(true),
 	}
 
-	envVars := []corev1.EnvVar{
-		{Name: "HTTP_PROXY"},
-		{Name: "HTTPS_PROXY"},
-		{Name: "NO_PROXY"},
-		{Name: "OCM_AGENT_SECRET_NAME", Value: ocmAgent.Spec.TokenSecret},
-		{Name: "OCM_AGENT_CONFIGMAP_NAME", Value: ocmAgent.Name + oah.ConfigMapSuffix},
+	envVars, err := applyConfigurationFrom[[]*corev1ac.EnvVarApplyConfiguration](env)
+	if err != nil {
+		return nil, err
 	}
 
 	// Sort volume slices by name to keep the sequence stable.
 	sort.Slice(volumes, func(i, j int) bool {
-		return volumes[i].Name < volumes[j].Name
+		return *volumes[i].Name < *volumes[j].Name
 	})
 	sort.Slice(volumeMounts, func(i, j int) bool {
-		return volumeMounts[i].Name < volumeMounts[j].Name
+		return *volumeMounts[i].Name < *volumeMounts[j].Name
 	})
 
-	// Define resource limits for the config
-	resourceLimits := corev1.ResourceList{
-		corev1.ResourceCPU:    k8sresource.MustParse(oah.ResourceLimitsCPU),
-		corev1.ResourceMemory: k8sresource.MustParse(oah.ResourceLimitsMemory),
+	// The scheduling settings are built from the OcmAgent spec, which only holds the fields set by the user
+	affinity, err := applyConfigurationFrom[*corev1ac.AffinityApplyConfiguration](buildAffinity(ocmAgent))
+	if err != nil {
+		return nil, err
 	}
-	// Define resource requests for the config
-	resourceRequests := corev1.ResourceList{
-		corev1.ResourceCPU:    k8sresource.MustParse(oah.ResourceRequestsCPU),
-		corev1.ResourceMemory: k8sresource.MustParse(oah.ResourceRequestsMemory),
+	tolerations, err := applyConfigurationFrom[[]*corev1ac.TolerationApplyConfiguration](buildTolerations(ocmAgent))
+	if err != nil {
+		return nil, err
+	}
+	topologySpreadConstraints, err := applyConfigurationFrom[[]*corev1ac.TopologySpreadConstraintApplyConfiguration](ocmAgent.Spec.TopologySpreadConstraints)
+	if err != nil {
+		return nil, err
 	}
+	resources := buildResourceRequirements(ocmAgent)
 
 	// Construct the command arguments of the agent
 	ocmAgentCommand := buildOCMAgentArgs(ocmAgent)
 
-	replicas := ocmAgent.Spec.Replicas
-	dep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-			Labels:    labels,
-		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
-			Selector: &labelSelectors,
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
-				},
-				Spec: corev1.PodSpec{
-					Volumes:            volumes,
-					ServiceAccountName: oah.OCMAgentServiceAccount,
-					Affinity: &corev1.Affinity{
-						NodeAffinity: &corev1.NodeAffinity{
-							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
-								Preference: corev1.NodeSelectorTerm{
-									MatchExpressions: []corev1.NodeSelectorRequirement{{
-										Key:      "node-role.kubernetes.io/infra",
-										Operator: corev1.NodeSelectorOpExists,
-									}},
-								},
-								Weight: 1,
-							}},
-						},
-						PodAntiAffinity: &corev1.PodAntiAffinity{
-							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
-								{
-									Weight: 100,
-									PodAffinityTerm: corev1.PodAffinityTerm{
-										LabelSelector: &metav1.LabelSelector{
-											MatchLabels: map[string]string{
-												"app": "ocm-agent",
-											},
-										},
-										TopologyKey: "kubernetes.io/hostname",
-									},
-								},
-							},
-						},
-					},
-					Tolerations: []corev1.Toleration{{
-						Operator: corev1.TolerationOpExists,
-						Effect:   corev1.TaintEffectNoSchedule,
+	podSpec := corev1ac.PodSpec().
+		WithVolumes(volumes...).
+		WithServiceAccountName(oah.OCMAgentServiceAccount).
+		WithAffinity(affinity).
+		WithTolerations(tolerations...).
+		WithTopologySpreadConstraints(topologySpreadConstraints...).
+		WithContainers(corev1ac.Container().
+			WithEnv(envVars...).
+			WithVolumeMounts(volumeMounts...).
+			WithImage(ocmAgent.Spec.OcmAgentImage).
+			WithCommand(ocmAgentCommand...).
+			WithName(ocmAgent.Name).
+			WithPorts(corev1ac.ContainerPort().
+				WithContainerPort(oah.OCMAgentPort).
+				WithName(oah.OCMAgentPortName)).
+			WithReadinessProbe(corev1ac.Probe().
+				WithHTTPGet(corev1ac.HTTPGetAction().
+					WithScheme(corev1.URISchemeHTTP).
+					WithPath(oah.OCMAgentReadyzPath).
+					WithPort(intstr.FromInt(oah.OCMAgentPort)))).
+			WithLivenessProbe(corev1ac.Probe().
+				WithHTTPGet(corev1ac.HTTPGetAction().
+					WithScheme(corev1.URISchemeHTTP).
+					WithPath(oah.OCMAgentLivezPath).
+					WithPort(intstr.FromInt(oah.OCMAgentPort)))).
+			WithResources(corev1ac.ResourceRequirements().
+				WithLimits(resources.Limits).
+				WithRequests(resources.Requests)))
+	if len(ocmAgent.Spec.NodeSelector) > 0 {
+		podSpec.WithNodeSelector(ocmAgent.Spec.NodeSelector)
+	}
+	if ocmAgent.Spec.PriorityClassName != "" {
+		podSpec.WithPriorityClassName(ocmAgent.Spec.PriorityClassName)
+	}
+
+	dep := appsv1ac.Deployment(namespacedName.Name, namespacedName.Namespace).
+		WithLabels(labels).
+		WithSpec(appsv1ac.DeploymentSpec().
+			WithReplicas(ocmAgent.Spec.Replicas).
+			WithSelector(metav1ac.LabelSelector().
+				WithMatchLabels(labels)).
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithLabels(labels).
+				WithSpec(podSpec)))
+	return dep, nil
+}
+
+// buildAffinity returns the default affinity of the OCM Agent pods, which prefers infra nodes and
+// spreading the pods across nodes. Each type of affinity set in the OcmAgent spec replaces the default.
+func buildAffinity(ocmAgent ocmagentv1alpha1.OcmAgent) *corev1.Affinity {
+	affinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+				Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
 						Key:      "node-role.kubernetes.io/infra",
+						Operator: corev1.NodeSelectorOpExists,
 					}},
-					Containers: []corev1.Container{{
-						Env:          envVars,
-						VolumeMounts: volumeMounts,
-						Image:        ocmAgent.Spec.OcmAgentImage,
-						Command:      ocmAgentCommand,
-						Name:         ocmAgent.Name,
-						Ports: []corev1.ContainerPort{{
-							ContainerPort: oah.OCMAgentPort,
-							Name:          oah.OCMAgentPortName,
-						}},
-						ReadinessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Scheme: corev1.URISchemeHTTP,
-									Path:   oah.OCMAgentReadyzPath,
-									Port:   intstr.FromInt(oah.OCMAgentPort),
-								},
-							},
-						},
-						LivenessProbe: &corev1.Probe{
-							ProbeHandler: corev1.ProbeHandler{
-								HTTPGet: &corev1.HTTPGetAction{
-									Scheme: corev1.URISchemeHTTP,
-									Path:   oah.OCMAgentLivezPath,
-									Port:   intstr.FromInt(oah.OCMAgentPort),
-								},
+				},
+				Weight: 1,
+			}},
+		},
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"app": ocmAgent.Name,
 							},
 						},
-						Resources: corev1.ResourceRequirements{
-							Limits:   resourceLimits,
-							Requests: resourceRequests,
-						},
-					}},
+						TopologyKey: "kubernetes.io/hostname",
+					},
 				},
 			},
 		},
 	}
-	return dep
+
+	if override := ocmAgent.Spec.Affinity; override != nil {
+		if override.NodeAffinity != nil {
+			affinity.NodeAffinity = override.NodeAffinity.DeepCopy()
+		}
+		if override.PodAffinity != nil {
+			affinity.PodAffinity = override.PodAffinity.DeepCopy()
+		}
+		if override.PodAntiAffinity != nil {
+			affinity.PodAntiAffinity = override.PodAntiAffinity.DeepCopy()
+		}
+	}
+
+	return affinity
+}
+
+// buildTolerations returns the toleration of the infra node taint followed by the tolerations
+// set in the OcmAgent spec
+func buildTolerations(ocmAgent ocmagentv1alpha1.OcmAgent) []corev1.Toleration {
+	infraToleration := corev1.Toleration{
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+		Key:      "node-role.kubernetes.io/infra",
+	}
+	tolerations := []corev1.Toleration{infraToleration}
+	for _, t := range ocmAgent.Spec.Tolerations {
+		if t.MatchToleration(&infraToleration) {
+			continue
+		}
+		tolerations = append(tolerations, *t.DeepCopy())
+	}
+	return tolerations
+}
+
+// buildResourceRequirements returns the default resource requests and limits of the OCM Agent
+// container, overridden by any resources set in the OcmAgent spec. A default limit below a request
+// set in the spec is raised to the request, and a default request above a limit set in the spec is
+// lowered to the limit, so that the requests never exceed the limits.
+func buildResourceRequirements(ocmAgent ocmagentv1alpha1.OcmAgent) corev1.ResourceRequirements {
+	// Define resource limits for the config
+	resourceLimits := corev1.ResourceList{
+		corev1.ResourceCPU:    k8sresource.MustParse(oah.ResourceLimitsCPU),
+		corev1.ResourceMemory: k8sresource.MustParse(oah.ResourceLimitsMemory),
+	}
+	// Define resource requests for the config
+	resourceRequests := corev1.ResourceList{
+		corev1.ResourceCPU:    k8sresource.MustParse(oah.ResourceRequestsCPU),
+		corev1.ResourceMemory: k8sresource.MustParse(oah.ResourceRequestsMemory),
+	}
+
+	if ocmAgent.Spec.Resources != nil {
+		for name, quantity := range ocmAgent.Spec.Resources.Limits {
+			resourceLimits[name] = quantity.DeepCopy()
+		}
+		for name, quantity := range ocmAgent.Spec.Resources.Requests {
+			resourceRequests[name] = quantity.DeepCopy()
+		}
+		for name, request := range ocmAgent.Spec.Resources.Requests {
+			if _, ok := ocmAgent.Spec.Resources.Limits[name]; ok {
+				continue
+			}
+			if limit, ok := resourceLimits[name]; ok && request.Cmp(limit) > 0 {
+				resourceLimits[name] = request.DeepCopy()
+			}
+		}
+		for name, limit := range ocmAgent.Spec.Resources.Limits {
+			if _, ok := ocmAgent.Spec.Resources.Requests[name]; ok {
+				continue
+			}
+			if request, ok := resourceRequests[name]; ok && request.Cmp(limit) > 0 {
+				resourceRequests[name] = limit.DeepCopy()
+			}
+		}
+	}
+
+	return corev1.ResourceRequirements{
+		Limits:   resourceLimits,
+		Requests: resourceRequests,
+	}
 }
 
 // buildOCMAgentArgs returns the full command argument list to run the OCM Agent
@@ -251,50 +297,23 @@ func buildOCMAgentArgs(ocmAgent ocmagentv1alpha1.OcmAgent) []string {
 // and that its configuration matches what is expected.
 func (o *ocmAgentHandler) ensureDeployment(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) error {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name)
-	foundResource := &appsv1.Deployment{}
-	populationFunc := func() appsv1.Deployment {
-		return buildOCMAgentDeployment(ocmAgent)
-	}
 
 	envVars, err := o.buildEnvVars(ctx, ocmAgent)
 	if err != nil {
 		return err
 	}
 
-	// Populate the resource with template and append the env vars
-	resource := populationFunc()
-	resource.Spec.Template.Spec.Containers[0].Env = envVars
+	resource, err := buildOCMAgentDeployment(ocmAgent, envVars)
+	if err != nil {
+		return err
+	}
 
-	// Does the resource already exist?
 	o.Log.Info("ensuring deployment exists", "resource", namespacedName.String())
-	if err := o.Client.Get(ctx, namespacedName, foundResource); err != nil {
-		if k8serrors.IsNotFound(err) {
-			// It does not exist, so must be created.
-			o.Log.Info("An OCMAgent deployment does not exist; will be created.")
-			// Set the controller reference
-			if err := controllerutil.SetControllerReference(&ocmAgent, &resource, o.Scheme); err != nil {
-				return err
-			}
-			// and create it
-			err = o.Client.Create(ctx, &resource)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Return unexpectedly
-			return err
-		}
-	} else {
-		// It does exist, check if it is what we expected
-		if deploymentConfigChanged(foundResource, &resource, ocmAgent, o.Log) {
-			// Specs aren't equal, update and fix.
-			o.Log.Info("An OCMAgent deployment exists but contains unexpected configuration. Restoring.")
-			foundResource.Spec = *resource.Spec.DeepCopy()
-			if err = o.Client.Update(ctx, foundResource); err != nil {
-				return err
-			}
-		}
+	applied, result, err := o.applyResource(ctx, &ocmAgent, resource)
+	if err != nil {
+		return err
 	}
+	o.recordApplyEvent(&ocmAgent, applied, result)
 	return nil
 }
 
@@ -313,7 +332,7 @@ func (o *ocmAgentHandler) ensureDeploymentDeleted(ctx context.Context, ocmAgent
 			return nil
 		}
 	}
-	err := o.Client.Delete(ctx, foundResource)
+	err := o.deleteResource(ctx, &ocmAgent, foundResource)
 	if err != nil {
 		return err
 	}
@@ -323,6 +342,8 @@ func (o *ocmAgentHandler) ensureDeploymentDeleted(ctx context.Context, ocmAgent
 // restartOCMAgentPods triggers a rolling restart of the ocm-agent deployment
 // by updating a timestamp annotation in the deployment's pod template.
 // This causes Kubernetes to restart the pods to pick up the updated secret.
+// The annotation is patched with its own field manager, so that applying the
+// deployment neither owns nor removes it.
 func (o *ocmAgentHandler) restartOCMAgentPods(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) error {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name)
 	foundDeployment := &appsv1.Deployment{}
@@ -331,13 +352,14 @@ func (o *ocmAgentHandler) restartOCMAgentPods(ctx context.Context, ocmAgent ocma
 		return fmt.Errorf("failed to get ocm-agent deployment %s: %w", namespacedName.String(), err)
 	}
 
+	patchBase := client.MergeFrom(foundDeployment.DeepCopy())
 	if foundDeployment.Spec.Template.Annotations == nil {
 		foundDeployment.Spec.Template.Annotations = make(map[string]string)
 	}
 	restartTime := time.Now().Format(time.RFC3339)
 	foundDeployment.Spec.Template.Annotations["ocm-agent-operator/restartedAt"] = restartTime
 
-	if err := o.Client.Update(ctx, foundDeployment); err != nil {
+	if err := o.Client.Patch(ctx, foundDeployment, patchBase, client.FieldOwner(oah.RestartFieldManager)); err != nil {
 		o.Log.Error(err, "Failed to restart ocm-agent deployment")
 		return fmt.Errorf("failed to restart ocm-agent deployment %s: %w", namespacedName.String(), err)
 	}
@@ -345,95 +367,13 @@ func (o *ocmAgentHandler) restartOCMAgentPods(ctx context.Context, ocmAgent ocma
 	return nil
 }
 
-// compareContainers compares container specs between current and expected deployments
-func compareContainers(current, expected *appsv1.Deployment, containerName string, log logr.Logger) bool {
-	var curImage, expImage string
-	var curReadinessProbeHTTPGet, curLivenessProbeHTTPGet, expReadinessProbeHTTPGet, expLivenessProbeHTTPGet *corev1.HTTPGetAction
-	var curEnvs, expEnvs []corev1.EnvVar
-
-	// Find current container spec
-	for i, c := range current.Spec.Template.Spec.Containers {
-		if containerName == c.Name {
-			curImage = current.Spec.Template.Spec.Containers[i].Image
-			if current.Spec.Template.Spec.Containers[i].ReadinessProbe != nil {
-				curReadinessProbeHTTPGet = current.Spec.Template.Spec.Containers[i].ReadinessProbe.HTTPGet
-			}
-			if current.Spec.Template.Spec.Containers[i].LivenessProbe != nil {
-				curLivenessProbeHTTPGet = current.Spec.Template.Spec.Containers[i].LivenessProbe.HTTPGet
-			}
-			curEnvs = current.Spec.Template.Spec.Containers[i].Env
-			break
-		}
-	}
-
-	// Find expected container spec
-	for i, c := range expected.Spec.Template.Spec.Containers {
-		if containerName == c.Name {
-			expImage = expected.Spec.Template.Spec.Containers[i].Image
-			expReadinessProbeHTTPGet = expected.Spec.Template.Spec.Containers[i].ReadinessProbe.HTTPGet
-			expLivenessProbeHTTPGet = expected.Spec.Template.Spec.Containers[i].LivenessProbe.HTTPGet
-			expEnvs = expected.Spec.Template.Spec.Containers[i].Env
-			break
-		}
-	}
-
-	if len(curImage) == 0 {
-		log.V(2).Info(fmt.Sprintf("current deployment %s/%s did not contain expected %s container", current.Namespace, current.Name, containerName))
-		return true
-	}
-
-	return curImage != expImage ||
-		!reflect.DeepEqual(curReadinessProbeHTTPGet, expReadinessProbeHTTPGet) ||
-		!reflect.DeepEqual(curLivenessProbeHTTPGet, expLivenessProbeHTTPGet) ||
-		!reflect.DeepEqual(curEnvs, expEnvs)
-}
-
-// deploymentConfigChanged flags if the two supplied deployments differ in configuration
-// that the OCM Agent Operator manages
-func deploymentConfigChanged(current, expected *appsv1.Deployment, ocmAgent ocmagentv1alpha1.OcmAgent, log logr.Logger) bool {
-	// Compare labels
-	if !reflect.DeepEqual(current.Labels, expected.Labels) {
-		return true
-	}
-	if !reflect.DeepEqual(current.Spec.Template.Labels, expected.Spec.Template.Labels) {
-		return true
-	}
-
-	// Compare containers
-	for _, name := range []string{ocmAgent.Name} {
-		if compareContainers(current, expected, name, log) {
-			return true
-		}
-	}
-
-	// Compare replicas
-	if *(current.Spec.Replicas) != *(expected.Spec.Replicas) {
-		log.V(2).Info(fmt.Sprintf("current deployment %s/%s did not contain expected replicas: %v", current.Namespace, current.Name, *(expected.Spec.Replicas)))
-		return true
-	}
-
-	// Compare affinity
-	if !reflect.DeepEqual(current.Spec.Template.Spec.Affinity, expected.Spec.Template.Spec.Affinity) {
-		log.V(2).Info(fmt.Sprintf("current deployment %s/%s did not contain expected affinity", current.Namespace, current.Name))
-		return true
-	}
-
-	// Compare tolerations
-	if !reflect.DeepEqual(current.Spec.Template.Spec.Tolerations, expected.Spec.Template.Spec.Tolerations) {
-		log.V(2).Info(fmt.Sprintf("current deployment %s/%s did not contain expected tolerations", current.Namespace, current.Name))
-		return true
-	}
-
-	return false
-}
-
 // buildEnvVars build the slice of environments to set to the OCM Agent deployment
 func (o *ocmAgentHandler) buildEnvVars(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) ([]corev1.EnvVar, error) {
 	envVars := make([]corev1.EnvVar, 0, 5)
 	proxy := oconfigv1.Proxy{}
 	err := o.Client.Get(ctx, oah.ProxyNamespacedName, &proxy)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", ErrProxyUnavailable, err)
 	}
 
 	proxyStatus := proxy.Status
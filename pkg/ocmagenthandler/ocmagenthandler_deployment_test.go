@@ -2,15 +2,18 @@ package ocmagenthandler
 
 import (
 	"context"
-	"reflect"
 
 	oconfigv1 "github.com/openshift/api/config/v1"
 	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
 	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrs "k8s.io/apimachinery/pkg/api/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"go.uber.org/mock/gomock"
@@ -22,6 +25,14 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// buildTestDeployment builds the OCM Agent deployment with the given environment and decodes its
+// apply configuration
+func buildTestDeployment(ocmAgent ocmagentv1alpha1.OcmAgent, env ...corev1.EnvVar) appsv1.Deployment {
+	ac, err := buildOCMAgentDeployment(ocmAgent, env)
+	Expect(err).To(BeNil())
+	return fromApplyConfiguration[appsv1.Deployment](ac)
+}
+
 var _ = Describe("OCM Agent Deployment Handler", func() {
 	var (
 		mockClient *clientmocks.MockClient
@@ -38,15 +49,16 @@ var _ = Describe("OCM Agent Deployment Handler", func() {
 		testOcmAgent = testconst.TestOCMAgent
 		testHSOcmAgent = testconst.TestHSOCMAgent
 		testOcmAgentHandler = ocmAgentHandler{
-			Client: mockClient,
-			Log:    testconst.Logger,
-			Scheme: testconst.Scheme,
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
 		}
 	})
 
 	Context("When building an OCM Agent Deployment", func() {
 		It("deploys with the expected configured values", func() {
-			deployment := buildOCMAgentDeployment(testOcmAgent)
+			deployment := buildTestDeployment(testOcmAgent)
 			Expect(deployment.Name).To(Equal(testOcmAgent.Name))
 			Expect(deployment.Namespace).To(Equal(ocmagenthandler.OCMAgentNamespace))
 			Expect(*deployment.Spec.Replicas).To(Equal(testOcmAgent.Spec.Replicas))
@@ -89,14 +101,112 @@ var _ = Describe("OCM Agent Deployment Handler", func() {
 			podAntiAffinity := deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0]
 			Expect(podAntiAffinity.Weight).To(Equal(int32(100)))
 			Expect(podAntiAffinity.PodAffinityTerm.TopologyKey).To(Equal("kubernetes.io/hostname"))
-			Expect(podAntiAffinity.PodAffinityTerm.LabelSelector.MatchLabels).To(HaveKeyWithValue("app", "ocm-agent"))
+			Expect(podAntiAffinity.PodAffinityTerm.LabelSelector.MatchLabels).To(HaveKeyWithValue("app", testOcmAgent.Name))
+
+		})
+	})
+
+	Context("When building an OCM Agent Deployment with resource overrides", func() {
+		It("overrides only the resources that are set", func() {
+			testOcmAgent.Spec.Resources = &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: k8sresource.MustParse("1Gi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: k8sresource.MustParse("256Mi"),
+				},
+			}
+			deployment := buildTestDeployment(testOcmAgent)
+			resources := deployment.Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Limits.Memory().String()).To(Equal("1Gi"))
+			Expect(resources.Requests.Memory().String()).To(Equal("256Mi"))
+			Expect(resources.Limits.Cpu().String()).To(Equal(ocmagenthandler.ResourceLimitsCPU))
+			Expect(resources.Requests.Cpu().String()).To(Equal(ocmagenthandler.ResourceRequestsCPU))
+		})
+
+		It("raises the default limit to a request above it", func() {
+			testOcmAgent.Spec.Resources = &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: k8sresource.MustParse("256Mi"),
+				},
+			}
+			deployment := buildTestDeployment(testOcmAgent)
+			resources := deployment.Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Requests.Memory().String()).To(Equal("256Mi"))
+			Expect(resources.Limits.Memory().String()).To(Equal("256Mi"))
+			Expect(resources.Limits.Cpu().String()).To(Equal(ocmagenthandler.ResourceLimitsCPU))
+		})
+
+		It("lowers the default request to a limit below it", func() {
+			testOcmAgent.Spec.Resources = &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: k8sresource.MustParse("16Mi"),
+				},
+			}
+			deployment := buildTestDeployment(testOcmAgent)
+			resources := deployment.Spec.Template.Spec.Containers[0].Resources
+			Expect(resources.Limits.Memory().String()).To(Equal("16Mi"))
+			Expect(resources.Requests.Memory().String()).To(Equal("16Mi"))
+			Expect(resources.Requests.Cpu().String()).To(Equal(ocmagenthandler.ResourceRequestsCPU))
+		})
+	})
+
+	Context("When building an OCM Agent Deployment with scheduling settings", func() {
+		It("uses the default scheduling settings when none are set", func() {
+			deployment := buildTestDeployment(testOcmAgent)
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.NodeSelector).To(BeEmpty())
+			Expect(podSpec.Tolerations).To(HaveLen(1))
+			Expect(podSpec.Tolerations[0].Key).To(Equal("node-role.kubernetes.io/infra"))
+			Expect(podSpec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			antiAffinityTerm := podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+			Expect(antiAffinityTerm.LabelSelector.MatchLabels).To(Equal(deployment.Spec.Template.Labels))
+			Expect(podSpec.TopologySpreadConstraints).To(BeEmpty())
+			Expect(podSpec.PriorityClassName).To(BeEmpty())
+		})
+
+		It("applies the scheduling settings of the OcmAgent", func() {
+			testOcmAgent.Spec.NodeSelector = map[string]string{"hypershift.openshift.io/control-plane": "true"}
+			testOcmAgent.Spec.Tolerations = []corev1.Toleration{
+				{Key: "hypershift.openshift.io/control-plane", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			}
+			testOcmAgent.Spec.Affinity = &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      "hypershift.openshift.io/control-plane",
+								Operator: corev1.NodeSelectorOpExists,
+							}},
+						}},
+					},
+				},
+			}
+			testOcmAgent.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+			}}
+			testOcmAgent.Spec.PriorityClassName = "system-cluster-critical"
 
+			deployment := buildTestDeployment(testOcmAgent)
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.NodeSelector).To(Equal(testOcmAgent.Spec.NodeSelector))
+			// the infra toleration is not duplicated
+			Expect(podSpec.Tolerations).To(HaveLen(2))
+			Expect(podSpec.Tolerations[1]).To(Equal(testOcmAgent.Spec.Tolerations[0]))
+			Expect(podSpec.Affinity.NodeAffinity).To(Equal(testOcmAgent.Spec.Affinity.NodeAffinity))
+			// the default pod anti-affinity is kept as it is not overridden
+			Expect(podSpec.Affinity.PodAntiAffinity).NotTo(BeNil())
+			Expect(podSpec.TopologySpreadConstraints).To(Equal(testOcmAgent.Spec.TopologySpreadConstraints))
+			Expect(podSpec.PriorityClassName).To(Equal("system-cluster-critical"))
 		})
 	})
 
 	Context("When building an OCM Agent HS Deployment", func() {
 		It("deploys with the expected configured values", func() {
-			deployment := buildOCMAgentDeployment(testHSOcmAgent)
+			deployment := buildTestDeployment(testHSOcmAgent)
 			Expect(deployment.Name).To(Equal(testHSOcmAgent.Name))
 			Expect(deployment.Namespace).To(Equal(ocmagenthandler.OCMAgentNamespace))
 			Expect(*deployment.Spec.Replicas).To(Equal(testHSOcmAgent.Spec.Replicas))
@@ -147,7 +257,7 @@ var _ = Describe("OCM Agent Deployment Handler", func() {
 		var testProxy, testNoProxy oconfigv1.Proxy
 		BeforeEach(func() {
 			testNamespacedName = ocmagenthandler.BuildNamespacedName(testOcmAgent.Name)
-			testDeployment = buildOCMAgentDeployment(testOcmAgent)
+			testDeployment = buildTestDeployment(testOcmAgent)
 			testProxy = oconfigv1.Proxy{
 				Status: oconfigv1.ProxyStatus{
 					HTTPProxy: "proxy.test:8080",
@@ -159,32 +269,26 @@ var _ = Describe("OCM Agent Deployment Handler", func() {
 
 		When("the OCM Agent deployment already exists", func() {
 			When("the deployment differs from what is expected", func() {
-				BeforeEach(func() {
-					replicas := int32(50)
-					testDeployment.Spec.Replicas = &replicas
-					testDeployment.Spec.Template.Spec.Containers[0].Image = "nope"
-				})
-				It("updates the deployment", func() {
-					goldenDeployment := buildOCMAgentDeployment(testOcmAgent)
+				It("applies the expected deployment", func() {
+					goldenDeployment := buildTestDeployment(testOcmAgent)
+					appliedDeployment := &appsv1.Deployment{}
 					gomock.InOrder(
 						mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(2, testProxy),
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testDeployment),
-						mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-							func(ctx context.Context, d *appsv1.Deployment, opts ...client.UpdateOptions) error {
-								Expect(d.Spec.Replicas).To(Equal(goldenDeployment.Spec.Replicas))
-								Expect(d.Spec.Template.Spec.Containers[0].Image).To(Equal(goldenDeployment.Spec.Template.Spec.Containers[0].Image))
-								return nil
-							}),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "2", appliedDeployment),
 					)
 					err := testOcmAgentHandler.ensureDeployment(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
+					Expect(appliedDeployment.Spec.Replicas).To(Equal(goldenDeployment.Spec.Replicas))
+					Expect(appliedDeployment.Spec.Template.Spec.Containers[0].Image).To(Equal(goldenDeployment.Spec.Template.Spec.Containers[0].Image))
 				})
 			})
 			When("the deployment matches what is expected", func() {
-				It("does not update the deployment", func() {
+				It("does not fail when the apply changes nothing", func() {
 					gomock.InOrder(
 						mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(2, testNoProxy),
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testDeployment),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
 					)
 					err := testOcmAgentHandler.ensureDeployment(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
@@ -198,23 +302,39 @@ var _ = Describe("OCM Agent Deployment Handler", func() {
 					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(2, testProxy),
 				)
 				envVars, _ := testOcmAgentHandler.buildEnvVars(testconst.Context, testOcmAgent)
-				testDeployment.Spec.Template.Spec.Containers[0].Env = envVars
+				testDeployment = buildTestDeployment(testOcmAgent, envVars...)
 			})
 			It("creates the deployment", func() {
-				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testDeployment.Name)
+				appliedDeployment := &appsv1.Deployment{}
 				gomock.InOrder(
 					mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).SetArg(2, testProxy),
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).Return(notFound),
-					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-						func(ctx context.Context, d *appsv1.Deployment, opts ...client.CreateOptions) error {
-							Expect(reflect.DeepEqual(d.Spec, testDeployment.Spec)).To(BeTrue())
-							Expect(d.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
-							Expect(*d.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
-							Expect(*d.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", appliedDeployment),
+				)
+				err := testOcmAgentHandler.ensureDeployment(testconst.Context, testOcmAgent)
+				Expect(err).To(BeNil())
+				Expect(equality.Semantic.DeepEqual(appliedDeployment.Spec, testDeployment.Spec)).To(BeTrue())
+				Expect(appliedDeployment.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
+				Expect(*appliedDeployment.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
+				Expect(*appliedDeployment.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
+			})
+		})
+
+		When("the OCM Agent pods are restarted", func() {
+			It("patches the restart annotation with its own field manager", func() {
+				gomock.InOrder(
+					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testDeployment),
+					mockClient.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any(), client.FieldOwner(ocmagenthandler.RestartFieldManager)).Times(1).DoAndReturn(
+						func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+							Expect(patch.Type()).To(Equal(types.MergePatchType))
+							data, err := patch.Data(obj)
+							Expect(err).To(BeNil())
+							Expect(string(data)).To(ContainSubstring("ocm-agent-operator/restartedAt"))
+							Expect(string(data)).NotTo(ContainSubstring("replicas"))
 							return nil
 						}),
 				)
-				err := testOcmAgentHandler.ensureDeployment(testconst.Context, testOcmAgent)
+				err := testOcmAgentHandler.restartOCMAgentPods(testconst.Context, testOcmAgent)
 				Expect(err).To(BeNil())
 			})
 		})
@@ -241,57 +361,5 @@ var _ = Describe("OCM Agent Deployment Handler", func() {
 				})
 			})
 		})
-
-		When("checking if the OCM Agent deployment has been changed", func() {
-			var goldenDeployment appsv1.Deployment
-			BeforeEach(func() {
-				goldenDeployment = buildOCMAgentDeployment(testOcmAgent)
-			})
-			It("should detect a label change", func() {
-				testDeployment.Labels = map[string]string{"dummy": "value"}
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("should detect an image change", func() {
-				testDeployment.Spec.Template.Spec.Containers[0].Image = "something else"
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("should handle missing readiness probe", func() {
-				testDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe = nil
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("should handle missing liveness probe", func() {
-				testDeployment.Spec.Template.Spec.Containers[0].LivenessProbe = nil
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("should detect a readiness probe change", func() {
-				testDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet = nil
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("should detect a liveness probe change", func() {
-				testDeployment.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet = nil
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("should detect a replica change", func() {
-				replicas := int32(5000)
-				testDeployment.Spec.Replicas = &replicas
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("should detect a affinity change", func() {
-				testDeployment.Spec.Template.Spec.Affinity = nil
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeTrue())
-			})
-			It("not detect a change if there are no differences", func() {
-				changed := deploymentConfigChanged(&testDeployment, &goldenDeployment, testOcmAgent, testconst.Logger)
-				Expect(changed).To(BeFalse())
-			})
-		})
 	})
 })
@@ -0,0 +1,85 @@
+package ocmagenthandler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+// Reasons of the events recorded against an OcmAgent
+const (
+	eventReasonCreated            = "Created"
+	eventReasonUpdated            = "Updated"
+	eventReasonDriftRestored      = "DriftRestored"
+	eventReasonAccessTokenRotated = "AccessTokenRotated"
+	eventReasonPodsRestarted      = "PodsRestarted"
+	eventReasonPodRestartFailed   = "PodRestartFailed"
+	eventReasonDeleteFailed       = "DeleteFailed"
+)
+
+// Actions of the events recorded against an OcmAgent
+const (
+	eventActionApply   = "Apply"
+	eventActionRestart = "Restart"
+	eventActionDelete  = "Delete"
+)
+
+// recordApplyEvent logs, counts and records an event against the OcmAgent when applying one of its
+// resources created, updated or restored it from drift. Skipped unmanaged resources are only logged.
+func (o *ocmAgentHandler) recordApplyEvent(ocmAgent *ocmagentv1alpha1.OcmAgent, obj client.Object, result applyResult) {
+	o.countApplyResult(ocmAgent, obj, result)
+	resource := o.describeResource(obj)
+	switch result {
+	case applyResultCreated:
+		o.Log.Info("Created OCM Agent resource", "resource", resource)
+		o.Recorder.Eventf(ocmAgent, obj, corev1.EventTypeNormal, eventReasonCreated, eventActionApply,
+			"Created %s", resource)
+	case applyResultUpdated:
+		o.Log.Info("Updated OCM Agent resource", "resource", resource)
+		o.Recorder.Eventf(ocmAgent, obj, corev1.EventTypeNormal, eventReasonUpdated, eventActionApply,
+			"Updated %s to the expected configuration", resource)
+	case applyResultDriftRestored:
+		o.Log.Info("OCM Agent resource contained unexpected configuration; restored", "resource", resource)
+		o.Recorder.Eventf(ocmAgent, obj, corev1.EventTypeNormal, eventReasonDriftRestored, eventActionApply,
+			"Restored the expected configuration of %s", resource)
+	case applyResultSkipped:
+		o.Log.Info("Skipping unmanaged OCM Agent resource", "resource", resource, "annotation", ocmagentv1alpha1.UnmanagedAnnotation)
+	}
+}
+
+// deleteResource deletes an OCM Agent resource and records a warning event against the
+// OcmAgent when the deletion fails. Resources annotated as unmanaged are left in place.
+func (o *ocmAgentHandler) deleteResource(ctx context.Context, ocmAgent *ocmagentv1alpha1.OcmAgent, obj client.Object) error {
+	if isUnmanaged(obj) {
+		o.Log.Info("Not deleting unmanaged OCM Agent resource", "resource", o.describeResource(obj),
+			"annotation", ocmagentv1alpha1.UnmanagedAnnotation)
+		return nil
+	}
+	if err := o.Client.Delete(ctx, obj); err != nil {
+		o.Recorder.Eventf(ocmAgent, obj, corev1.EventTypeWarning, eventReasonDeleteFailed, eventActionDelete,
+			"Failed to delete %s: %v", o.describeResource(obj), err)
+		return err
+	}
+	return nil
+}
+
+// describeResource returns the kind and namespaced name of a resource for use in events and logs
+func (o *ocmAgentHandler) describeResource(obj client.Object) string {
+	return fmt.Sprintf("%s %s", o.resourceKind(obj), client.ObjectKeyFromObject(obj).String())
+}
+
+// resourceKind returns the kind of a resource, looking it up in the scheme for typed objects
+func (o *ocmAgentHandler) resourceKind(obj client.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		if gvk, err := apiutil.GVKForObject(obj, o.Scheme); err == nil {
+			kind = gvk.Kind
+		}
+	}
+	return kind
+}
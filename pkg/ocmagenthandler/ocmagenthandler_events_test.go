@@ -0,0 +1,88 @@
+package ocmagenthandler
+
+import (
+	"fmt"
+
+	"go.uber.org/mock/gomock"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OCM Agent Events Handler", func() {
+	var (
+		mockClient   *clientmocks.MockClient
+		mockCtrl     *gomock.Controller
+		fakeRecorder *events.FakeRecorder
+
+		testOcmAgent        ocmagentv1alpha1.OcmAgent
+		testOcmAgentHandler ocmAgentHandler
+		testService         corev1.Service
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		fakeRecorder = events.NewFakeRecorder(10)
+		testOcmAgent = testconst.TestOCMAgent
+		testOcmAgentHandler = ocmAgentHandler{
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: fakeRecorder,
+		}
+		testService = fromApplyConfiguration[corev1.Service](buildOCMAgentService(testOcmAgent))
+	})
+
+	Context("Recording the outcome of applying a resource", func() {
+		It("records a creation", func() {
+			testOcmAgentHandler.recordApplyEvent(&testOcmAgent, &testService, applyResultCreated)
+			Expect(fakeRecorder.Events).To(Receive(Equal(fmt.Sprintf("Normal Created Created Service %s/%s",
+				testService.Namespace, testService.Name))))
+		})
+		It("records an update", func() {
+			testOcmAgentHandler.recordApplyEvent(&testOcmAgent, &testService, applyResultUpdated)
+			Expect(fakeRecorder.Events).To(Receive(Equal(fmt.Sprintf("Normal Updated Updated Service %s/%s to the expected configuration",
+				testService.Namespace, testService.Name))))
+		})
+		It("records a drift restore", func() {
+			testOcmAgentHandler.recordApplyEvent(&testOcmAgent, &testService, applyResultDriftRestored)
+			Expect(fakeRecorder.Events).To(Receive(Equal(fmt.Sprintf("Normal DriftRestored Restored the expected configuration of Service %s/%s",
+				testService.Namespace, testService.Name))))
+		})
+		It("records nothing when the resource is unchanged", func() {
+			testOcmAgentHandler.recordApplyEvent(&testOcmAgent, &testService, applyResultUnchanged)
+			Expect(fakeRecorder.Events).NotTo(Receive())
+		})
+	})
+
+	Context("Deleting a resource", func() {
+		It("records nothing when the deletion succeeds", func() {
+			mockClient.EXPECT().Delete(gomock.Any(), &testService).Return(nil)
+			err := testOcmAgentHandler.deleteResource(testconst.Context, &testOcmAgent, &testService)
+			Expect(err).To(BeNil())
+			Expect(fakeRecorder.Events).NotTo(Receive())
+		})
+		It("leaves an unmanaged resource in place", func() {
+			testService.Annotations = map[string]string{ocmagentv1alpha1.UnmanagedAnnotation: "true"}
+			mockClient.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+			err := testOcmAgentHandler.deleteResource(testconst.Context, &testOcmAgent, &testService)
+			Expect(err).To(BeNil())
+			Expect(fakeRecorder.Events).NotTo(Receive())
+		})
+		It("records a warning when the deletion fails", func() {
+			mockClient.EXPECT().Delete(gomock.Any(), &testService).Return(fmt.Errorf("fake error"))
+			err := testOcmAgentHandler.deleteResource(testconst.Context, &testOcmAgent, &testService)
+			Expect(err).NotTo(BeNil())
+			Expect(fakeRecorder.Events).To(Receive(Equal(fmt.Sprintf("Warning DeleteFailed Failed to delete Service %s/%s: fake error",
+				testService.Namespace, testService.Name))))
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package ocmagenthandler
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
+)
+
+// applyResultOutcomes maps the results of applying a resource that changed it to the outcome they are counted as
+var applyResultOutcomes = map[applyResult]string{
+	applyResultCreated:       localmetrics.ResourceOutcomeCreate,
+	applyResultUpdated:       localmetrics.ResourceOutcomeUpdate,
+	applyResultDriftRestored: localmetrics.ResourceOutcomeDriftRestore,
+}
+
+// countApplyResult counts the outcome of applying one of the resources of the OcmAgent when it changed the resource
+func (o *ocmAgentHandler) countApplyResult(ocmAgent *ocmagentv1alpha1.OcmAgent, obj client.Object, result applyResult) {
+	if outcome, ok := applyResultOutcomes[result]; ok {
+		localmetrics.UpdateMetricResourceReconcileTotal(ocmAgent.Name, o.resourceKind(obj), outcome)
+	}
+}
+
+// observeEnsure runs a function ensuring the resources of the OcmAgent of the supplied kind, and
+// records how long it took and whether it succeeded
+func observeEnsure(ocmAgent ocmagentv1alpha1.OcmAgent, kind string, ensure func() error) error {
+	start := time.Now()
+	err := ensure()
+	localmetrics.UpdateMetricResourceReconcileDuration(ocmAgent.Name, kind, time.Since(start))
+	if err != nil {
+		localmetrics.UpdateMetricResourceReconcileTotal(ocmAgent.Name, kind, localmetrics.ResourceOutcomeError)
+		return err
+	}
+	localmetrics.UpdateMetricResourceLastReconcileSuccess(ocmAgent.Name, kind, time.Now())
+	return nil
+}
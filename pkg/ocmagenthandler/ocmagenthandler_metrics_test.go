@@ -0,0 +1,69 @@
+package ocmagenthandler
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
+	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OCM Agent Metrics Handler", func() {
+	var (
+		testOcmAgent        ocmagentv1alpha1.OcmAgent
+		testOcmAgentHandler ocmAgentHandler
+		testService         corev1.Service
+	)
+
+	BeforeEach(func() {
+		localmetrics.MetricResourceReconcileTotal.Reset()
+		localmetrics.MetricResourceReconcileDuration.Reset()
+		localmetrics.MetricResourceLastReconcileSuccess.Reset()
+		testOcmAgent = testconst.TestOCMAgent
+		testOcmAgentHandler = ocmAgentHandler{
+			Log:    testconst.Logger,
+			Scheme: testconst.Scheme,
+		}
+		testService = fromApplyConfiguration[corev1.Service](buildOCMAgentService(testOcmAgent))
+	})
+
+	Context("Counting the outcome of applying a resource", func() {
+		It("counts the resources that were changed by their kind", func() {
+			testOcmAgentHandler.countApplyResult(&testOcmAgent, &testService, applyResultCreated)
+			testOcmAgentHandler.countApplyResult(&testOcmAgent, &testService, applyResultDriftRestored)
+			testOcmAgentHandler.countApplyResult(&testOcmAgent, &testService, applyResultDriftRestored)
+			Expect(testutil.ToFloat64(localmetrics.MetricResourceReconcileTotal.WithLabelValues(
+				testOcmAgent.Name, "Service", localmetrics.ResourceOutcomeCreate))).To(Equal(float64(1)))
+			Expect(testutil.ToFloat64(localmetrics.MetricResourceReconcileTotal.WithLabelValues(
+				testOcmAgent.Name, "Service", localmetrics.ResourceOutcomeDriftRestore))).To(Equal(float64(2)))
+		})
+		It("does not count the resources that were left alone", func() {
+			testOcmAgentHandler.countApplyResult(&testOcmAgent, &testService, applyResultUnchanged)
+			testOcmAgentHandler.countApplyResult(&testOcmAgent, &testService, applyResultSkipped)
+			Expect(testutil.CollectAndCount(localmetrics.MetricResourceReconcileTotal)).To(Equal(0))
+		})
+	})
+
+	Context("Observing an ensure step", func() {
+		It("records the duration and the time of the last success", func() {
+			Expect(observeEnsure(testOcmAgent, "Service", func() error { return nil })).To(Succeed())
+			Expect(testutil.CollectAndCount(localmetrics.MetricResourceReconcileDuration)).To(Equal(1))
+			Expect(testutil.ToFloat64(localmetrics.MetricResourceLastReconcileSuccess.WithLabelValues(
+				testOcmAgent.Name, "Service"))).To(BeNumerically(">", 0))
+			Expect(testutil.CollectAndCount(localmetrics.MetricResourceReconcileTotal)).To(Equal(0))
+		})
+		It("counts a failure without recording a success", func() {
+			err := observeEnsure(testOcmAgent, "Service", func() error { return fmt.Errorf("fake error") })
+			Expect(err).To(MatchError("fake error"))
+			Expect(testutil.ToFloat64(localmetrics.MetricResourceReconcileTotal.WithLabelValues(
+				testOcmAgent.Name, "Service", localmetrics.ResourceOutcomeError))).To(Equal(float64(1)))
+			Expect(testutil.CollectAndCount(localmetrics.MetricResourceLastReconcileSuccess)).To(Equal(0))
+		})
+	})
+})
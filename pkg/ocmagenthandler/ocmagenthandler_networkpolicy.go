@@ -2,14 +2,13 @@ package ocmagenthandler
 
 import (
 	"context"
-	"reflect"
 
 	"k8s.io/apimachinery/pkg/types"
 
 	netv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	netv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
@@ -32,40 +31,28 @@ func buildNetworkPolicyName(ocmAgent ocmagentv1alpha1.OcmAgent, namespace string
 	return namespacedName
 }
 
-func buildNetworkPolicy(ocmAgent ocmagentv1alpha1.OcmAgent, namespace string) netv1.NetworkPolicy {
+func buildNetworkPolicy(ocmAgent ocmagentv1alpha1.OcmAgent, namespace string) *netv1ac.NetworkPolicyApplyConfiguration {
 	var (
 		namespacedName    types.NamespacedName
-		namespaceSelector *metav1.LabelSelector
+		namespaceSelector *metav1ac.LabelSelectorApplyConfiguration
 	)
 
 	namespacedName = buildNetworkPolicyName(ocmAgent, namespace)
 
-	namespaceSelector = &metav1.LabelSelector{
-		MatchLabels: map[string]string{"kubernetes.io/metadata.name": namespace},
-	}
-
-	np := netv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-			Labels: map[string]string{
-				"app": ocmAgent.Name,
-			},
-		},
-		Spec: netv1.NetworkPolicySpec{
-			PodSelector: metav1.LabelSelector{
-				MatchLabels: map[string]string{"app": ocmAgent.Name},
-			},
-			Ingress: []netv1.NetworkPolicyIngressRule{{
-				From: []netv1.NetworkPolicyPeer{{
-					NamespaceSelector: namespaceSelector},
-				}},
-			},
-			PolicyTypes: []netv1.PolicyType{
-				netv1.PolicyTypeIngress,
-			},
-		},
-	}
+	namespaceSelector = metav1ac.LabelSelector().
+		WithMatchLabels(map[string]string{"kubernetes.io/metadata.name": namespace})
+
+	np := netv1ac.NetworkPolicy(namespacedName.Name, namespacedName.Namespace).
+		WithLabels(map[string]string{
+			"app": ocmAgent.Name,
+		}).
+		WithSpec(netv1ac.NetworkPolicySpec().
+			WithPodSelector(metav1ac.LabelSelector().
+				WithMatchLabels(map[string]string{"app": ocmAgent.Name})).
+			WithIngress(netv1ac.NetworkPolicyIngressRule().
+				WithFrom(netv1ac.NetworkPolicyPeer().
+					WithNamespaceSelector(namespaceSelector))).
+			WithPolicyTypes(netv1.PolicyTypeIngress))
 
 	return np
 }
@@ -90,46 +77,14 @@ func (o *ocmAgentHandler) ensureAllNetworkPolicies(ctx context.Context, ocmAgent
 // ensureNetworkPolicy ensures that an OCMAgent NetworkPolicy exists on the cluster
 // and that its configuration matches what is expected.
 func (o *ocmAgentHandler) ensureNetworkPolicy(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent, namespace string) error {
+	resource := buildNetworkPolicy(ocmAgent, namespace)
 
-	namespacedName := buildNetworkPolicyName(ocmAgent, namespace)
-
-	foundResource := &netv1.NetworkPolicy{}
-	populationFunc := func() netv1.NetworkPolicy {
-		return buildNetworkPolicy(ocmAgent, namespace)
-	}
-	// Does the resource already exist?
-	o.Log.Info("ensuring networkpolicy exists", "resource", namespacedName.String())
-	if err := o.Client.Get(ctx, namespacedName, foundResource); err != nil {
-		if k8serrors.IsNotFound(err) {
-			// It does not exist, so must be created.
-			o.Log.Info("An OCMAgent NetworkPolicy does not exist; will be created.")
-			// Populate the resource with the template
-			resource := populationFunc()
-			// Set the controller reference
-			if err := controllerutil.SetControllerReference(&ocmAgent, &resource, o.Scheme); err != nil {
-				return err
-			}
-			// and create it
-			err = o.Client.Create(ctx, &resource)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Return unexpectedly
-			return err
-		}
-	} else {
-		// It does exist, check if it is what we expected
-		resource := populationFunc()
-		if !reflect.DeepEqual(foundResource.Spec, resource.Spec) {
-			// Specs aren't equal, update and fix.
-			o.Log.Info("An OCMAgent network policy exists but contains unexpected configuration. Restoring.")
-			foundResource.Spec = *resource.Spec.DeepCopy()
-			if err = o.Client.Update(ctx, foundResource); err != nil {
-				return err
-			}
-		}
+	o.Log.Info("ensuring networkpolicy exists", "resource", buildNetworkPolicyName(ocmAgent, namespace).String())
+	applied, result, err := o.applyResource(ctx, &ocmAgent, resource)
+	if err != nil {
+		return err
 	}
+	o.recordApplyEvent(&ocmAgent, applied, result)
 	return nil
 }
 
@@ -165,7 +120,7 @@ func (o *ocmAgentHandler) ensureNetworkPolicyDeleted(ctx context.Context, ocmAge
 			return nil
 		}
 	}
-	err := o.Client.Delete(ctx, foundResource)
+	err := o.deleteResource(ctx, &ocmAgent, foundResource)
 	if err != nil {
 		return err
 	}
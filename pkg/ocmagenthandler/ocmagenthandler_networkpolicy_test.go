@@ -1,7 +1,6 @@
 package ocmagenthandler
 
 import (
-	"context"
 	"reflect"
 
 	"go.uber.org/mock/gomock"
@@ -10,7 +9,7 @@ import (
 
 	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/client-go/tools/events"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -39,16 +38,17 @@ var _ = Describe("OCM Agent NetworkPolicy Handler", func() {
 		testOcmAgent = testconst.TestOCMAgent
 		testFleetOcmAgent = testconst.TestHSOCMAgent
 		testOcmAgentHandler = ocmAgentHandler{
-			Client: mockClient,
-			Log:    testconst.Logger,
-			Scheme: testconst.Scheme,
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
 		}
 	})
 
 	Context("When building an OCM Agent NetworkPolicy", func() {
 		BeforeEach(func() {
 			testNamespace = oah.NamespaceMonitorng
-			networkPolicy = buildNetworkPolicy(testOcmAgent, testNamespace)
+			networkPolicy = fromApplyConfiguration[netv1.NetworkPolicy](buildNetworkPolicy(testOcmAgent, testNamespace))
 		})
 
 		It("Should have the expected name, namespace and labels", func() {
@@ -76,31 +76,26 @@ var _ = Describe("OCM Agent NetworkPolicy Handler", func() {
 		BeforeEach(func() {
 			testNamespace = oah.NamespaceOBO
 			testNamespacedName = buildNetworkPolicyName(testOcmAgent, testNamespace)
-			networkPolicy = buildNetworkPolicy(testOcmAgent, testNamespace)
+			networkPolicy = fromApplyConfiguration[netv1.NetworkPolicy](buildNetworkPolicy(testOcmAgent, testNamespace))
 		})
 		When("the network policy already exists", func() {
 			When("the network policy differs from what is expected", func() {
-				BeforeEach(func() {
-					networkPolicy.Spec.PodSelector.MatchLabels = map[string]string{"fake": "fake"}
-				})
-				It("updates the networkpolicy", func() {
-					goldenNetworkPolicy := buildNetworkPolicy(testOcmAgent, testNamespace)
+				It("applies the expected networkpolicy", func() {
+					appliedNetworkPolicy := &netv1.NetworkPolicy{}
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, networkPolicy),
-						mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-							func(ctx context.Context, d *netv1.NetworkPolicy, opts ...client.UpdateOptions) error {
-								Expect(reflect.DeepEqual(d.Spec, goldenNetworkPolicy.Spec)).To(BeTrue())
-								return nil
-							}),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "2", appliedNetworkPolicy),
 					)
 					err := testOcmAgentHandler.ensureNetworkPolicy(testconst.Context, testOcmAgent, testNamespace)
 					Expect(err).To(BeNil())
+					Expect(reflect.DeepEqual(appliedNetworkPolicy.Spec, networkPolicy.Spec)).To(BeTrue())
 				})
 			})
 			When("the networkpolicy matches what is expected", func() {
-				It("does not update the networkpolicy", func() {
+				It("does not fail when the apply changes nothing", func() {
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, networkPolicy),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
 					)
 					err := testOcmAgentHandler.ensureNetworkPolicy(testconst.Context, testOcmAgent, testNamespace)
 					Expect(err).To(BeNil())
@@ -110,20 +105,17 @@ var _ = Describe("OCM Agent NetworkPolicy Handler", func() {
 
 		When("the OCM Agent networkpolicy does not already exist", func() {
 			It("creates the networkpolicy", func() {
-				notFound := k8serrs.NewNotFound(schema.GroupResource{}, networkPolicy.Name)
+				appliedNetworkPolicy := &netv1.NetworkPolicy{}
 				gomock.InOrder(
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Return(notFound),
-					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
-						func(ctx context.Context, d *netv1.NetworkPolicy, opts ...client.CreateOptions) error {
-							Expect(reflect.DeepEqual(d.Spec, networkPolicy.Spec)).To(BeTrue())
-							Expect(d.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
-							Expect(*d.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
-							Expect(*d.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
-							return nil
-						}),
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", appliedNetworkPolicy),
 				)
 				err := testOcmAgentHandler.ensureNetworkPolicy(testconst.Context, testOcmAgent, testNamespace)
 				Expect(err).To(BeNil())
+				Expect(reflect.DeepEqual(appliedNetworkPolicy.Spec, networkPolicy.Spec)).To(BeTrue())
+				Expect(appliedNetworkPolicy.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
+				Expect(*appliedNetworkPolicy.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
+				Expect(*appliedNetworkPolicy.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
 			})
 		})
 	})
@@ -136,7 +128,7 @@ var _ = Describe("OCM Agent NetworkPolicy Handler", func() {
 		})
 		When("network policy exists", func() {
 			It("should be able to delete the networkpolicy", func() {
-				networkPolicy = buildNetworkPolicy(testOcmAgent, testNamespace)
+				networkPolicy = fromApplyConfiguration[netv1.NetworkPolicy](buildNetworkPolicy(testOcmAgent, testNamespace))
 				mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, networkPolicy)
 				mockClient.EXPECT().Delete(gomock.Any(), gomock.Any())
 				err := testOcmAgentHandler.ensureNetworkPolicyDeleted(testconst.Context, testOcmAgent, testNamespace)
@@ -157,7 +149,7 @@ var _ = Describe("OCM Agent NetworkPolicy Handler", func() {
 		When("creating a non-fleet ocm-agent", func() {
 			It("should have the 2 networkpolicies created", func() {
 				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
-				mockClient.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).MinTimes(2)
+				mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
 				err := testOcmAgentHandler.ensureAllNetworkPolicies(testconst.Context, testOcmAgent)
 				Expect(err).To(BeNil())
 			})
@@ -165,7 +157,7 @@ var _ = Describe("OCM Agent NetworkPolicy Handler", func() {
 		When("creating a fleet ocm-agent", func() {
 			It("should have the 3 networkpolicies created", func() {
 				mockClient.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
-				mockClient.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
+				mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
 				err := testOcmAgentHandler.ensureAllNetworkPolicies(testconst.Context, testFleetOcmAgent)
 				Expect(err).To(BeNil())
 			})
@@ -2,70 +2,39 @@ package ocmagenthandler
 
 import (
 	"context"
-	"reflect"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 	v1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	policyv1ac "k8s.io/client-go/applyconfigurations/policy/v1"
 )
 
-func buildOCMAgentPodDisruptionBudget(ocmAgent ocmagentv1alpha1.OcmAgent) *v1.PodDisruptionBudget {
+func buildOCMAgentPodDisruptionBudget(ocmAgent ocmagentv1alpha1.OcmAgent) *policyv1ac.PodDisruptionBudgetApplyConfiguration {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name + oah.PDBSuffix)
 
-	return &v1.PodDisruptionBudget{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-		},
-		Spec: v1.PodDisruptionBudgetSpec{
-			MinAvailable: &intstr.IntOrString{
-				Type:   intstr.Int,
-				IntVal: 1,
-			},
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
+	return policyv1ac.PodDisruptionBudget(namespacedName.Name, namespacedName.Namespace).
+		WithSpec(policyv1ac.PodDisruptionBudgetSpec().
+			WithMinAvailable(intstr.FromInt32(1)).
+			WithSelector(metav1ac.LabelSelector().
+				WithMatchLabels(map[string]string{
 					"app": ocmAgent.Name,
-				},
-			},
-		},
-	}
+				})))
 }
 
 // ensurePodDisruptionBudget ensures that an OCMAgent PDB exists on the cluster
 // and that its configuration matches what is expected.
 func (o *ocmAgentHandler) ensurePodDisruptionBudget(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) error {
 	pdb := buildOCMAgentPodDisruptionBudget(ocmAgent)
-	foundPDB := &v1.PodDisruptionBudget{}
-
-	// Check if the PDB already exists
-	if err := o.Client.Get(ctx, types.NamespacedName{
-		Name: pdb.Name, Namespace: pdb.Namespace}, foundPDB); err != nil {
 
-		if k8serrors.IsNotFound(err) {
-			o.Log.Info("A Pod Disruption Budget does not exist; will be created", "PDB.Namespace", pdb.Namespace, "PDB.Name", pdb.Name)
-			// Set the controller reference
-			if err := controllerutil.SetControllerReference(&ocmAgent, pdb, o.Scheme); err != nil {
-				return err
-			}
-			// Create it now
-			return o.Client.Create(ctx, pdb)
-		}
+	applied, result, err := o.applyResource(ctx, &ocmAgent, pdb)
+	if err != nil {
 		return err
-	} else {
-		if !reflect.DeepEqual(foundPDB.Spec, pdb.Spec) {
-			foundPDB.Spec = *pdb.Spec.DeepCopy()
-			o.Log.Info("Updating Pod Disruption Budget", "PDB.Namespace", foundPDB.Namespace, "PDB.Name", foundPDB.Name)
-			err = o.Client.Update(ctx, foundPDB)
-			if err != nil {
-				return err
-			}
-		}
 	}
+	o.recordApplyEvent(&ocmAgent, applied, result)
 	return nil
 }
 
@@ -75,7 +44,7 @@ func (o *ocmAgentHandler) ensurePodDisruptionBudgetDeleted(ctx context.Context,
 	foundPDB := &v1.PodDisruptionBudget{}
 
 	if err := o.Client.Get(ctx, types.NamespacedName{
-		Name: pdb.Name, Namespace: pdb.Namespace}, foundPDB); err != nil {
+		Name: *pdb.Name, Namespace: *pdb.Namespace}, foundPDB); err != nil {
 		if k8serrors.IsNotFound(err) {
 			return nil
 		}
@@ -83,5 +52,5 @@ func (o *ocmAgentHandler) ensurePodDisruptionBudgetDeleted(ctx context.Context,
 	}
 
 	o.Log.Info("Ensuring Pod Disruption Budget is removed", "PDB.Namespace", foundPDB.Namespace, "PDB.Name", foundPDB.Name)
-	return o.Client.Delete(ctx, foundPDB)
+	return o.deleteResource(ctx, &ocmAgent, foundPDB)
 }
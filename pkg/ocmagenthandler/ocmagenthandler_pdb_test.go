@@ -1,19 +1,15 @@
 package ocmagenthandler
 
 import (
-	"context"
-
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
 	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
 	v1 "k8s.io/api/policy/v1"
 	k8serrs "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/client-go/tools/events"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -34,9 +30,10 @@ var _ = Describe("OCM Agent Pod Disruption Budget Handler", func() {
 		mockClient = clientmocks.NewMockClient(mockCtrl)
 		testOcmAgent = testconst.TestOCMAgent
 		testOcmAgentHandler = ocmAgentHandler{
-			Client: mockClient,
-			Log:    testconst.Logger,
-			Scheme: testconst.Scheme,
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
 		}
 	})
 
@@ -46,81 +43,43 @@ var _ = Describe("OCM Agent Pod Disruption Budget Handler", func() {
 
 		BeforeEach(func() {
 			testNamespacedName = oah.BuildNamespacedName(testOcmAgent.Name + "-pdb")
-			testPDB = *buildOCMAgentPodDisruptionBudget(testOcmAgent)
+			testPDB = fromApplyConfiguration[v1.PodDisruptionBudget](buildOCMAgentPodDisruptionBudget(testOcmAgent))
 		})
 
 		It("creates the PDB if it does not exist", func() {
-			notFound := k8serrs.NewNotFound(schema.GroupResource{}, "")
-			mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Return(notFound)
-			mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
-				func(ctx context.Context, pdb *v1.PodDisruptionBudget, opts ...client.CreateOptions) error {
-					Expect(pdb.Name).To(Equal(testPDB.Name))
-					Expect(pdb.Namespace).To(Equal(testPDB.Namespace))
-					Expect(pdb.Spec).To(Equal(testPDB.Spec))
-					return nil
-				},
-			)
-			err := testOcmAgentHandler.ensurePodDisruptionBudget(testconst.Context, testOcmAgent)
-			Expect(err).NotTo(HaveOccurred())
-		})
-
-		It("updates the PDB if MinAvailable differs from the expected", func() {
-			differentPDB := testPDB
-			differentPDB.Spec.MinAvailable = &intstr.IntOrString{IntVal: 2}
-
-			// Mock the Get call to return this modified PDB
-			mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, differentPDB)
-
-			// Expect the Update to be called with the correct spec
-			mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-				func(ctx context.Context, pdb *v1.PodDisruptionBudget, opts ...client.UpdateOptions) error {
-					expectedPDB := buildOCMAgentPodDisruptionBudget(testOcmAgent)
-					Expect(pdb.Spec.MinAvailable).To(Equal(expectedPDB.Spec.MinAvailable))
-					return nil
-				},
+			appliedPDB := &v1.PodDisruptionBudget{}
+			gomock.InOrder(
+				expectGetResourceVersion(mockClient, testNamespacedName, ""),
+				expectApply(mockClient, "1", appliedPDB),
 			)
 			err := testOcmAgentHandler.ensurePodDisruptionBudget(testconst.Context, testOcmAgent)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(appliedPDB.Name).To(Equal(testPDB.Name))
+			Expect(appliedPDB.Namespace).To(Equal(testPDB.Namespace))
+			Expect(appliedPDB.Spec).To(Equal(testPDB.Spec))
+			Expect(*appliedPDB.OwnerReferences[0].Controller).To(BeTrue())
 		})
 
-		It("updates the PDB if Selector.MatchLabels differs from the expected", func() {
-			differentPDB := testPDB
-			differentPDB.Spec.Selector = &metav1.LabelSelector{
-				MatchLabels: map[string]string{"key": "oldValue"},
-			}
-
-			// Mock the Get call to return this modified PDB
-			mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, differentPDB)
-
-			// Expect the Update to be called with the correct spec
-			mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-				func(ctx context.Context, pdb *v1.PodDisruptionBudget, opts ...client.UpdateOptions) error {
-					expectedPDB := buildOCMAgentPodDisruptionBudget(testOcmAgent)
-					Expect(pdb.Spec.Selector.MatchLabels).To(Equal(expectedPDB.Spec.Selector.MatchLabels))
-					return nil
-				},
+		It("applies the expected PDB if it exists", func() {
+			appliedPDB := &v1.PodDisruptionBudget{}
+			gomock.InOrder(
+				expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+				expectApply(mockClient, "2", appliedPDB),
 			)
 			err := testOcmAgentHandler.ensurePodDisruptionBudget(testconst.Context, testOcmAgent)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(appliedPDB.Spec.MinAvailable).To(Equal(testPDB.Spec.MinAvailable))
+			Expect(appliedPDB.Spec.Selector.MatchLabels).To(Equal(testPDB.Spec.Selector.MatchLabels))
+			Expect(appliedPDB.Spec.MaxUnavailable).To(BeNil())
 		})
 
-		It("updates the PDB if MaxUnavailable differs from the expected", func() {
-			differentPDB := testPDB
-			differentPDB.Spec.MaxUnavailable = &intstr.IntOrString{IntVal: 1}
-
-			// Mock the Get call to return this modified PDB
-			mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).SetArg(2, differentPDB)
-
-			// Expect the Update to be called with the correct spec
-			mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
-				func(ctx context.Context, pdb *v1.PodDisruptionBudget, opts ...client.UpdateOptions) error {
-					expectedPDB := buildOCMAgentPodDisruptionBudget(testOcmAgent)
-					Expect(pdb.Spec.MaxUnavailable).To(Equal(expectedPDB.Spec.MaxUnavailable))
-					return nil
-				},
+		It("returns the error if the PDB cannot be applied", func() {
+			gomock.InOrder(
+				expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+				mockClient.EXPECT().Apply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(k8serrs.NewConflict(schema.GroupResource{}, testPDB.Name, nil)),
 			)
 			err := testOcmAgentHandler.ensurePodDisruptionBudget(testconst.Context, testOcmAgent)
-			Expect(err).NotTo(HaveOccurred())
+			Expect(err).To(HaveOccurred())
 		})
 
 		It("deletes the PDB if it exists", func() {
@@ -4,29 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 	"github.com/openshift/ocm-agent-operator/pkg/localmetrics"
 )
 
-func buildOCMAgentAccessTokenSecret(accessToken []byte, ocmAgent ocmagentv1alpha1.OcmAgent) corev1.Secret {
+func buildOCMAgentAccessTokenSecret(accessToken []byte, ocmAgent ocmagentv1alpha1.OcmAgent) *corev1ac.SecretApplyConfiguration {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Spec.TokenSecret)
-	secret := corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-		},
-		Data: map[string][]byte{
+	secret := corev1ac.Secret(namespacedName.Name, namespacedName.Namespace).
+		WithData(map[string][]byte{
 			oah.OCMAgentAccessTokenSecretKey: accessToken,
-		},
-	}
+		})
 	return secret
 }
 
@@ -35,59 +28,36 @@ func buildOCMAgentAccessTokenSecret(accessToken []byte, ocmAgent ocmagentv1alpha
 // Returns (wasUpdated bool, error) where wasUpdated indicates if the secret was created or updated.
 func (o *ocmAgentHandler) ensureAccessTokenSecret(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) (bool, error) {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Spec.TokenSecret)
-	foundResource := &corev1.Secret{}
 
 	clusterPullSecret, err := o.fetchAccessTokenPullSecret(ctx)
 	if err != nil {
 		o.Log.Error(err, "Failed to fetch pull-secret")
 		localmetrics.UpdateMetricPullSecretInvalid(ocmAgent.Name)
-		return false, err
+		return false, fmt.Errorf("%w: %w", ErrPullSecretInvalid, err)
 	}
 	localmetrics.ResetMetricPullSecretInvalid(ocmAgent.Name)
 
-	populationFunc := func() corev1.Secret {
-		return buildOCMAgentAccessTokenSecret(clusterPullSecret, ocmAgent)
+	resource := buildOCMAgentAccessTokenSecret(clusterPullSecret, ocmAgent)
+	applied, result, err := o.applyResource(ctx, &ocmAgent, resource)
+	if err != nil {
+		o.Log.Error(err, "Failed to apply secret")
+		return false, err
 	}
-
-	// Does the resource already exist?
-	if err := o.Client.Get(ctx, namespacedName, foundResource); err != nil {
-		if k8serrors.IsNotFound(err) {
-			// It does not exist, so must be created.
-			// Populate the resource with the template
-			resource := populationFunc()
-			// Set the controller reference
-			if err := controllerutil.SetControllerReference(&ocmAgent, &resource, o.Scheme); err != nil {
-				o.Log.Error(err, "Failed to set controller reference")
-				return false, err
-			}
-			// and create it
-			err = o.Client.Create(ctx, &resource)
-			if err != nil {
-				o.Log.Error(err, "Failed to create secret")
-				return false, err
-			}
-			o.Log.Info("Created ocm-access-token secret", "secret", namespacedName.String())
-			return true, nil // Secret was created
-		} else {
-			// Return unexpectedly
-			o.Log.Error(err, "Unexpected error fetching secret")
-			return false, err
-		}
-	} else {
-		// It does exist, check if it is what we expected
-		resource := populationFunc()
-		if !reflect.DeepEqual(foundResource.Data, resource.Data) {
-			// Update only the Data field to preserve server-managed metadata
-			foundResource.Data = resource.Data
-			if err = o.Client.Update(ctx, foundResource); err != nil {
-				o.Log.Error(err, "Failed to update secret")
-				return false, err
-			}
-			o.Log.Info("Updated ocm-access-token secret", "secret", namespacedName.String())
-			return true, nil // Secret was updated
-		}
+	switch result {
+	case applyResultUpdated:
+		o.countApplyResult(&ocmAgent, applied, result)
+		o.Log.Info("Rotated ocm-access-token secret", "secret", namespacedName.String())
+		o.Recorder.Eventf(&ocmAgent, applied, corev1.EventTypeNormal, eventReasonAccessTokenRotated, eventActionApply,
+			"Rotated the OCM access token in Secret %s from the cluster pull secret", namespacedName.String())
+		return true, nil
+	case applyResultCreated, applyResultDriftRestored:
+		o.recordApplyEvent(&ocmAgent, applied, result)
+		return true, nil
+	default:
+		// Secret matches the expected state or is unmanaged, no update needed
+		o.recordApplyEvent(&ocmAgent, applied, result)
+		return false, nil
 	}
-	return false, nil // Secret exists and matches expected state, no update needed
 }
 
 func (o *ocmAgentHandler) ensureFleetClientSecret(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) error {
@@ -122,7 +92,7 @@ func (o *ocmAgentHandler) ensureAccessTokenSecretDeleted(ctx context.Context, oc
 			return nil
 		}
 	}
-	err := o.Client.Delete(ctx, foundResource)
+	err := o.deleteResource(ctx, &ocmAgent, foundResource)
 	if err != nil {
 		return err
 	}
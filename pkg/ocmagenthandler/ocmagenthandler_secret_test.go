@@ -2,7 +2,6 @@ package ocmagenthandler
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -14,7 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/client-go/tools/events"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oahconst "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
@@ -53,9 +52,10 @@ var _ = Describe("OCM Agent Access Token Secret Handler", func() {
 		testOcmAgent = testconst.TestOCMAgent
 		testHSOcmAgent = testconst.TestHSOCMAgent
 		testOcmAgentHandler = ocmAgentHandler{
-			Client: mockClient,
-			Log:    testconst.Logger,
-			Scheme: testconst.Scheme,
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
 		}
 		testClusterPullSecretValue = []byte(fmt.Sprintf(`{
 			"auths": {
@@ -94,7 +94,7 @@ var _ = Describe("OCM Agent Access Token Secret Handler", func() {
 			cm := buildOCMAgentAccessTokenSecret(testOcmAccessTokenSecretValue, testOcmAgent)
 			Expect(cm.Data).Should(HaveKey(oahconst.OCMAgentAccessTokenSecretKey))
 			Expect(bytes.Compare(cm.Data[oahconst.OCMAgentAccessTokenSecretKey], testOcmAccessTokenSecretValue)).To(BeZero())
-			Expect(cm.Name).To(Equal(testOcmAgent.Spec.TokenSecret))
+			Expect(*cm.Name).To(Equal(testOcmAgent.Spec.TokenSecret))
 		})
 	})
 
@@ -103,36 +103,35 @@ var _ = Describe("OCM Agent Access Token Secret Handler", func() {
 		var testNamespacedName, testHSNamespacedName types.NamespacedName
 		BeforeEach(func() {
 			testNamespacedName = oahconst.BuildNamespacedName(testOcmAgent.Spec.TokenSecret)
-			testSecret = buildOCMAgentAccessTokenSecret(testOcmAccessTokenSecretValue, testOcmAgent)
+			testSecret = fromApplyConfiguration[corev1.Secret](buildOCMAgentAccessTokenSecret(testOcmAccessTokenSecretValue, testOcmAgent))
 			testHSNamespacedName = oahconst.BuildNamespacedName(testHSOcmAgent.Spec.TokenSecret)
 		})
 		When("the OCM Agent secret already exists", func() {
 			When("the secret differs from what is expected", func() {
-				BeforeEach(func() {
-					testSecret.Data = map[string][]byte{"fake": []byte("not the right value")}
-				})
-				It("updates the secret", func() {
+				It("updates the secret and records the rotation", func() {
 					goldenSecret := buildOCMAgentAccessTokenSecret(testOcmAccessTokenSecretValue, testOcmAgent)
+					appliedSecret := &corev1.Secret{}
+					fakeRecorder := events.NewFakeRecorder(1)
+					testOcmAgentHandler.Recorder = fakeRecorder
 					gomock.InOrder(
 						mockClient.EXPECT().Get(gomock.Any(), oahconst.PullSecretNamespacedName, gomock.Any()).Times(1).SetArg(2, testPullSecret),
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testSecret),
-						mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-							func(ctx context.Context, d *corev1.Secret, opts ...client.UpdateOptions) error {
-								Expect(d.Data).Should(HaveKey(oahconst.OCMAgentAccessTokenSecretKey))
-								Expect(bytes.Compare(d.Data[oahconst.OCMAgentAccessTokenSecretKey], goldenSecret.Data[oahconst.OCMAgentAccessTokenSecretKey])).To(BeZero())
-								return nil
-							}),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "2", appliedSecret),
 					)
 					updated, err := testOcmAgentHandler.ensureAccessTokenSecret(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
 					Expect(updated).To(BeTrue())
+					Expect(appliedSecret.Data).Should(HaveKey(oahconst.OCMAgentAccessTokenSecretKey))
+					Expect(bytes.Compare(appliedSecret.Data[oahconst.OCMAgentAccessTokenSecretKey], goldenSecret.Data[oahconst.OCMAgentAccessTokenSecretKey])).To(BeZero())
+					Expect(fakeRecorder.Events).To(Receive(HavePrefix("Normal AccessTokenRotated")))
 				})
 			})
 			When("the secret matches what is expected", func() {
 				It("does not update the secret", func() {
 					gomock.InOrder(
 						mockClient.EXPECT().Get(gomock.Any(), oahconst.PullSecretNamespacedName, gomock.Any()).Times(1).SetArg(2, testPullSecret),
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testSecret),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
 					)
 					updated, err := testOcmAgentHandler.ensureAccessTokenSecret(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
@@ -151,22 +150,19 @@ var _ = Describe("OCM Agent Access Token Secret Handler", func() {
 		})
 		When("the OCM Agent secret does not already exist", func() {
 			It("creates the secret", func() {
-				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testSecret.Name)
+				appliedSecret := &corev1.Secret{}
 				gomock.InOrder(
 					mockClient.EXPECT().Get(gomock.Any(), oahconst.PullSecretNamespacedName, gomock.Any()).Times(1).SetArg(2, testPullSecret),
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).Return(notFound),
-					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
-						func(ctx context.Context, d *corev1.Secret, opts ...client.CreateOptions) error {
-							Expect(reflect.DeepEqual(d.Data, testSecret.Data)).To(BeTrue())
-							Expect(d.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
-							Expect(*d.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
-							Expect(*d.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
-							return nil
-						}),
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", appliedSecret),
 				)
 				updated, err := testOcmAgentHandler.ensureAccessTokenSecret(testconst.Context, testOcmAgent)
 				Expect(err).To(BeNil())
 				Expect(updated).To(BeTrue())
+				Expect(reflect.DeepEqual(appliedSecret.Data, testSecret.Data)).To(BeTrue())
+				Expect(appliedSecret.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
+				Expect(*appliedSecret.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
+				Expect(*appliedSecret.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
 			})
 			It("return not found error for HS secret", func() {
 				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testSecret.Name)
@@ -223,7 +219,8 @@ ocm_agent_operator_pull_secret_invalid{ocmagent_name="test-ocm-agent"} 1
 			It("sets the correct metric", func() {
 				gomock.InOrder(
 					mockClient.EXPECT().Get(gomock.Any(), oahconst.PullSecretNamespacedName, gomock.Any()).Times(1).SetArg(2, testPullSecret),
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testSecret),
+					expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+					expectApply(mockClient, "1", nil),
 				)
 				updated, err := testOcmAgentHandler.ensureAccessTokenSecret(testconst.Context, testOcmAgent)
 				Expect(err).To(BeNil())
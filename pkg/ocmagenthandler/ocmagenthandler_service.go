@@ -2,115 +2,63 @@ package ocmagenthandler
 
 import (
 	"context"
-	"fmt"
-	"reflect"
-
-	"github.com/go-logr/logr"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 )
 
-func buildOCMAgentService(ocmAgent ocmagentv1alpha1.OcmAgent) corev1.Service {
+func buildOCMAgentService(ocmAgent ocmagentv1alpha1.OcmAgent) *corev1ac.ServiceApplyConfiguration {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name)
 	labels := map[string]string{
 		"app": ocmAgent.Name,
 	}
-	svc := corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-		},
-		Spec: corev1.ServiceSpec{
-			Selector: labels,
-			Ports: []corev1.ServicePort{{
-				TargetPort: intstr.FromInt(oah.OCMAgentPort),
-				Name:       oah.OCMAgentPortName,
-				Port:       oah.OCMAgentServicePort,
-				Protocol:   corev1.ProtocolTCP,
-			}},
-		},
-	}
+	svc := corev1ac.Service(namespacedName.Name, namespacedName.Namespace).
+		WithSpec(corev1ac.ServiceSpec().
+			WithSelector(labels).
+			WithPorts(corev1ac.ServicePort().
+				WithTargetPort(intstr.FromInt(oah.OCMAgentPort)).
+				WithName(oah.OCMAgentPortName).
+				WithPort(oah.OCMAgentServicePort).
+				WithProtocol(corev1.ProtocolTCP)))
 	return svc
 }
 
-func buildOCMAgentMetricsService(ocmAgent ocmagentv1alpha1.OcmAgent) corev1.Service {
+func buildOCMAgentMetricsService(ocmAgent ocmagentv1alpha1.OcmAgent) *corev1ac.ServiceApplyConfiguration {
 	metricsSVCname := ocmAgent.Name + "-metrics"
 	namespacedName := oah.BuildNamespacedName(metricsSVCname)
 	labels := map[string]string{
 		"app": ocmAgent.Name,
 	}
-	svc := corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-			Labels:    labels,
-		},
-		Spec: corev1.ServiceSpec{
-			Selector: labels,
-			Ports: []corev1.ServicePort{{
-				TargetPort: intstr.FromInt(oah.OCMAgentMetricsPort),
-				Name:       oah.OCMAgentMetricsPortName,
-				Port:       oah.OCMAgentMetricsServicePort,
-				Protocol:   corev1.ProtocolTCP,
-			}},
-		},
-	}
+	svc := corev1ac.Service(namespacedName.Name, namespacedName.Namespace).
+		WithLabels(labels).
+		WithSpec(corev1ac.ServiceSpec().
+			WithSelector(labels).
+			WithPorts(corev1ac.ServicePort().
+				WithTargetPort(intstr.FromInt(oah.OCMAgentMetricsPort)).
+				WithName(oah.OCMAgentMetricsPortName).
+				WithPort(oah.OCMAgentMetricsServicePort).
+				WithProtocol(corev1.ProtocolTCP)))
 	return svc
 }
 
-// ensureConfigMap ensures that an OCMAgent ConfigMap exists on the cluster
-// and that its configuration matches what is expected.
+// ensureService ensures that the OCMAgent Services exist on the cluster
+// and that their configuration matches what is expected.
 func (o *ocmAgentHandler) ensureService(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) error {
-	foundResource := &corev1.Service{}
-	buildOCMAgentSvcFunc := func() corev1.Service {
-		return buildOCMAgentService(ocmAgent)
-	}
-	buildOCMAgentMetricsSvcFunc := func() corev1.Service {
-		return buildOCMAgentMetricsService(ocmAgent)
-	}
-	oaSvc := buildOCMAgentSvcFunc()
-	oaMetricsSvc := buildOCMAgentMetricsSvcFunc()
+	oaSvc := buildOCMAgentService(ocmAgent)
+	oaMetricsSvc := buildOCMAgentMetricsService(ocmAgent)
 
-	// Does the resource already exist?
-	for _, svc := range []corev1.Service{oaSvc, oaMetricsSvc} {
-		svc := svc //prevent implicit memory aliasing
-		namespacedName := oah.BuildNamespacedName(svc.Name)
-		o.Log.Info("ensuring service exists", "resource", svc.Name)
-		if err := o.Client.Get(ctx, namespacedName, foundResource); err != nil {
-			if k8serrors.IsNotFound(err) {
-				// It does not exist, so must be created.
-				o.Log.Info("An OCMAgent service does not exist; will be created.")
-
-				// Set the controller reference
-				if err := controllerutil.SetControllerReference(&ocmAgent, &svc, o.Scheme); err != nil {
-					return err
-				}
-				err = o.Client.Create(ctx, &svc)
-				if err != nil {
-					return err
-				}
-			} else {
-				// Return unexpectedly
-				return err
-			}
-		} else {
-			// It does exist, check if it is what we expected
-			if serviceConfigChanged(foundResource, &svc, o.Log) {
-				// Specs aren't equal, update and fix.
-				o.Log.Info("An OCMAgent service exists but contains unexpected configuration. Restoring.")
-				foundResource.Spec = *svc.Spec.DeepCopy()
-				if err = o.Client.Update(ctx, foundResource); err != nil {
-					return err
-				}
-			}
+	for _, svc := range []*corev1ac.ServiceApplyConfiguration{oaSvc, oaMetricsSvc} {
+		o.Log.Info("ensuring service exists", "resource", *svc.Name)
+		applied, result, err := o.applyResource(ctx, &ocmAgent, svc)
+		if err != nil {
+			return err
 		}
+		o.recordApplyEvent(&ocmAgent, applied, result)
 	}
 	return nil
 }
@@ -132,29 +80,10 @@ func (o *ocmAgentHandler) ensureServiceDeleted(ctx context.Context, ocmAgent ocm
 				return nil
 			}
 		}
-		err := o.Client.Delete(ctx, foundResource)
+		err := o.deleteResource(ctx, &ocmAgent, foundResource)
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
-
-// serviceConfigChanged flags if the two supplied services differ in configuration
-// that the OCM Agent Operator manages
-func serviceConfigChanged(current, expected *corev1.Service, log logr.Logger) (changed bool) {
-	changed = false
-
-	if !reflect.DeepEqual(current.Labels, expected.Labels) {
-		changed = true
-	}
-	if !reflect.DeepEqual(current.Spec.Selector, expected.Spec.Selector) {
-		log.V(2).Info(fmt.Sprintf("current service %s/%s did not contain expected selectors", current.Namespace, current.Name))
-		changed = true
-	}
-	if !reflect.DeepEqual(current.Spec.Ports, expected.Spec.Ports) {
-		log.V(2).Info(fmt.Sprintf("current service %s/%s did not contain expected ports", current.Namespace, current.Name))
-		changed = true
-	}
-	return changed
-}
@@ -1,7 +1,6 @@
 package ocmagenthandler
 
 import (
-	"context"
 	"reflect"
 
 	"go.uber.org/mock/gomock"
@@ -10,7 +9,7 @@ import (
 	k8serrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/client-go/tools/events"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
@@ -35,9 +34,10 @@ var _ = Describe("OCM Agent Service Handler", func() {
 		mockClient = clientmocks.NewMockClient(mockCtrl)
 		testOcmAgent = testconst.TestOCMAgent
 		testOcmAgentHandler = ocmAgentHandler{
-			Client: mockClient,
-			Log:    testconst.Logger,
-			Scheme: testconst.Scheme,
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
 		}
 	})
 
@@ -45,8 +45,8 @@ var _ = Describe("OCM Agent Service Handler", func() {
 		It("Sets a correct name", func() {
 			svc := buildOCMAgentService(testOcmAgent)
 			metricsSvc := buildOCMAgentMetricsService(testOcmAgent)
-			Expect(svc.Name).To(Equal(testOcmAgent.Name))
-			Expect(metricsSvc.Name).To(Equal(testOcmAgent.Name + "-metrics"))
+			Expect(*svc.Name).To(Equal(testOcmAgent.Name))
+			Expect(*metricsSvc.Name).To(Equal(testOcmAgent.Name + "-metrics"))
 		})
 	})
 
@@ -55,35 +55,32 @@ var _ = Describe("OCM Agent Service Handler", func() {
 		var testNamespacedName, testMetricsNamespacedName types.NamespacedName
 		BeforeEach(func() {
 			testNamespacedName = oah.BuildNamespacedName(testOcmAgent.Name)
-			testService = buildOCMAgentService(testOcmAgent)
-			testMetricsService = buildOCMAgentMetricsService(testOcmAgent)
+			testService = fromApplyConfiguration[corev1.Service](buildOCMAgentService(testOcmAgent))
+			testMetricsService = fromApplyConfiguration[corev1.Service](buildOCMAgentMetricsService(testOcmAgent))
 			testMetricsNamespacedName = oah.BuildNamespacedName(testOcmAgent.Name + "-metrics")
 		})
 		When("the OCM Agent service already exists", func() {
 			When("the service differs from what is expected", func() {
-				BeforeEach(func() {
-					testService.Spec.Ports[0].Port = int32(9999)
-				})
-				It("updates the Service", func() {
-					goldenService := buildOCMAgentService(testOcmAgent)
+				It("applies the expected Service", func() {
+					appliedService := &corev1.Service{}
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testService),
-						mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-							func(ctx context.Context, d *corev1.Service, opts ...client.UpdateOptions) error {
-								Expect(reflect.DeepEqual(d.Spec, goldenService.Spec)).To(BeTrue())
-								return nil
-							}),
-						mockClient.EXPECT().Get(gomock.Any(), testMetricsNamespacedName, gomock.Any()).Times(1).SetArg(2, testMetricsService),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "2", appliedService),
+						expectGetResourceVersion(mockClient, testMetricsNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
 					)
 					err := testOcmAgentHandler.ensureService(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
+					Expect(reflect.DeepEqual(appliedService.Spec, testService.Spec)).To(BeTrue())
 				})
 			})
 			When("the Service matches what is expected", func() {
-				It("does not update the Service", func() {
+				It("does not fail when the apply changes nothing", func() {
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testService),
-						mockClient.EXPECT().Get(gomock.Any(), testMetricsNamespacedName, gomock.Any()).Times(1).SetArg(2, testMetricsService),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
+						expectGetResourceVersion(mockClient, testMetricsNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
 					)
 					err := testOcmAgentHandler.ensureService(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
@@ -92,21 +89,19 @@ var _ = Describe("OCM Agent Service Handler", func() {
 		})
 		When("the OCM Agent Service does not already exist", func() {
 			It("creates the Service", func() {
-				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testService.Name)
+				appliedService := &corev1.Service{}
 				gomock.InOrder(
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).Return(notFound),
-					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-						func(ctx context.Context, d *corev1.Service, opts ...client.CreateOptions) error {
-							Expect(reflect.DeepEqual(d.Spec, testService.Spec)).To(BeTrue())
-							Expect(d.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
-							Expect(*d.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
-							Expect(*d.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
-							return nil
-						}),
-					mockClient.EXPECT().Get(gomock.Any(), testMetricsNamespacedName, gomock.Any()).Times(1).SetArg(2, testMetricsService),
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", appliedService),
+					expectGetResourceVersion(mockClient, testMetricsNamespacedName, "1"),
+					expectApply(mockClient, "1", nil),
 				)
 				err := testOcmAgentHandler.ensureService(testconst.Context, testOcmAgent)
 				Expect(err).To(BeNil())
+				Expect(reflect.DeepEqual(appliedService.Spec, testService.Spec)).To(BeTrue())
+				Expect(appliedService.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
+				Expect(*appliedService.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
+				Expect(*appliedService.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
 			})
 		})
 		When("the OCM Agent Service should be removed", func() {
@@ -134,36 +129,4 @@ var _ = Describe("OCM Agent Service Handler", func() {
 			})
 		})
 	})
-
-	Context("When comparing two services", func() {
-		var currentService, expectedService corev1.Service
-		BeforeEach(func() {
-			currentService = buildOCMAgentService(testOcmAgent)
-			expectedService = buildOCMAgentService(testOcmAgent)
-		})
-		Context("When the labels are different", func() {
-			BeforeEach(func() {
-				currentService.Labels = map[string]string{"different": "value"}
-			})
-			It("flags them as different", func() {
-				r := serviceConfigChanged(&currentService, &expectedService, testconst.Logger)
-				Expect(r).To(BeTrue())
-			})
-		})
-		Context("When the ports are different", func() {
-			BeforeEach(func() {
-				currentService.Spec.Ports[0].Port = int32(9999)
-			})
-			It("flags them as different", func() {
-				r := serviceConfigChanged(&currentService, &expectedService, testconst.Logger)
-				Expect(r).To(BeTrue())
-			})
-		})
-		Context("When there are no differences", func() {
-			It("flags that there are none", func() {
-				r := serviceConfigChanged(&currentService, &expectedService, testconst.Logger)
-				Expect(r).To(BeFalse())
-			})
-		})
-	})
 })
@@ -2,37 +2,29 @@ package ocmagenthandler
 
 import (
 	"context"
-	"reflect"
 
 	monitorv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitorv1ac "github.com/prometheus-operator/prometheus-operator/pkg/client/applyconfiguration/monitoring/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
 )
 
-func buildOCMAgentServiceMonitor(ocmAgent ocmagentv1alpha1.OcmAgent) monitorv1.ServiceMonitor {
+func buildOCMAgentServiceMonitor(ocmAgent ocmagentv1alpha1.OcmAgent) *monitorv1ac.ServiceMonitorApplyConfiguration {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name + "-metrics")
 	labels := map[string]string{
 		"app": ocmAgent.Name,
 	}
-	sm := monitorv1.ServiceMonitor{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
-		},
-		Spec: monitorv1.ServiceMonitorSpec{
-			Selector: metav1.LabelSelector{
+	sm := monitorv1ac.ServiceMonitor(namespacedName.Name, namespacedName.Namespace).
+		WithSpec(monitorv1ac.ServiceMonitorSpec().
+			WithSelector(metav1.LabelSelector{
 				MatchLabels: labels,
-			},
-			Endpoints: []monitorv1.Endpoint{{
-				Port: oah.OCMAgentMetricsPortName,
-				Path: "/metrics",
-			}},
-		},
-	}
+			}).
+			WithEndpoints(monitorv1ac.Endpoint().
+				WithPort(oah.OCMAgentMetricsPortName).
+				WithPath("/metrics")))
 	return sm
 }
 
@@ -40,46 +32,15 @@ func buildOCMAgentServiceMonitor(ocmAgent ocmagentv1alpha1.OcmAgent) monitorv1.S
 // and that its configuration matches what is expected.
 func (o *ocmAgentHandler) ensureServiceMonitor(ctx context.Context, ocmAgent ocmagentv1alpha1.OcmAgent) error {
 	namespacedName := oah.BuildNamespacedName(ocmAgent.Name + "-metrics")
-	foundResource := &monitorv1.ServiceMonitor{}
-
-	populationFunc := func() monitorv1.ServiceMonitor {
-		return buildOCMAgentServiceMonitor(ocmAgent)
-	}
+	resource := buildOCMAgentServiceMonitor(ocmAgent)
 
-	// Does the resource already exist?
 	o.Log.Info("ensuring serviceMonitor exists", "resource", namespacedName.String())
-	if err := o.Client.Get(ctx, namespacedName, foundResource); err != nil {
-		if k8serrors.IsNotFound(err) {
-			// It does not exist, so must be created.
-			o.Log.Info("An OCMAgent serviceMonitor does not exist; will be created.")
-			// Populate the resource with the template
-			resource := populationFunc()
-			// Set the controller reference
-			if err := controllerutil.SetControllerReference(&ocmAgent, &resource, o.Scheme); err != nil {
-				return err
-			}
-			// and create it
-			err = o.Client.Create(ctx, &resource)
-			if err != nil {
-				return err
-			}
-		} else {
-			// Return unexpectedly
-			return err
-		}
-	} else {
-		// It does exist, check if it is what we expected
-		resource := populationFunc()
-		if !reflect.DeepEqual(foundResource.Spec, resource.Spec) {
-			// Update only the Spec field to preserve server-managed metadata
-			o.Log.Info("An OCMAgent serviceMonitor exists but contains unexpected configuration. Restoring.")
-			foundResource.Spec = resource.Spec
-			if err = o.Client.Update(ctx, foundResource); err != nil {
-				o.Log.Error(err, "Failed to update ServiceMonitor")
-				return err
-			}
-		}
+	applied, result, err := o.applyResource(ctx, &ocmAgent, resource)
+	if err != nil {
+		o.Log.Error(err, "Failed to apply ServiceMonitor")
+		return err
 	}
+	o.recordApplyEvent(&ocmAgent, applied, result)
 	return nil
 }
 
@@ -97,7 +58,7 @@ func (o *ocmAgentHandler) ensureServiceMonitorDeleted(ctx context.Context, ocmAg
 			return nil
 		}
 	}
-	err := o.Client.Delete(ctx, foundResource)
+	err := o.deleteResource(ctx, &ocmAgent, foundResource)
 	if err != nil {
 		return err
 	}
@@ -1,7 +1,6 @@
 package ocmagenthandler
 
 import (
-	"context"
 	"reflect"
 
 	monitorv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -11,7 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"k8s.io/client-go/tools/events"
 
 	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
 	oah "github.com/openshift/ocm-agent-operator/pkg/consts/ocmagenthandler"
@@ -42,16 +41,17 @@ var _ = Describe("OCM Agent ServiceMonitor Handler", func() {
 			Status: ocmagentv1alpha1.OcmAgentStatus{},
 		}
 		testOcmAgentHandler = ocmAgentHandler{
-			Client: mockClient,
-			Log:    testconst.Logger,
-			Scheme: testconst.Scheme,
+			Client:   mockClient,
+			Log:      testconst.Logger,
+			Scheme:   testconst.Scheme,
+			Recorder: &events.FakeRecorder{},
 		}
 	})
 
 	Context("When building an OCM Agent ServiceMonitor", func() {
 		It("Sets a correct name", func() {
 			sm := buildOCMAgentServiceMonitor(testOcmAgent)
-			Expect(sm.Name).To(Equal(testOcmAgent.Name + "-metrics"))
+			Expect(*sm.Name).To(Equal(testOcmAgent.Name + "-metrics"))
 		})
 	})
 
@@ -60,31 +60,26 @@ var _ = Describe("OCM Agent ServiceMonitor Handler", func() {
 		var testNamespacedName types.NamespacedName
 		BeforeEach(func() {
 			testNamespacedName = oah.BuildNamespacedName(testOcmAgent.Name + "-metrics")
-			testServiceMonitor = buildOCMAgentServiceMonitor(testOcmAgent)
+			testServiceMonitor = fromApplyConfiguration[monitorv1.ServiceMonitor](buildOCMAgentServiceMonitor(testOcmAgent))
 		})
 		When("the OCM Agent serviceMonitor already exists", func() {
 			When("the serviceMonitor differs from what is expected", func() {
-				BeforeEach(func() {
-					testServiceMonitor.Spec.Endpoints[0].Port = "test-port"
-				})
-				It("updates the ServiceMonitor", func() {
-					goldenSM := buildOCMAgentServiceMonitor(testOcmAgent)
+				It("applies the expected ServiceMonitor", func() {
+					appliedSM := &monitorv1.ServiceMonitor{}
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testServiceMonitor),
-						mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-							func(ctx context.Context, d *monitorv1.ServiceMonitor, opts ...client.UpdateOptions) error {
-								Expect(reflect.DeepEqual(d.Spec, goldenSM.Spec)).To(BeTrue())
-								return nil
-							}),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "2", appliedSM),
 					)
 					err := testOcmAgentHandler.ensureServiceMonitor(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
+					Expect(reflect.DeepEqual(appliedSM.Spec, testServiceMonitor.Spec)).To(BeTrue())
 				})
 			})
 			When("the ServiceMonitor matches what is expected", func() {
-				It("does not update the ServiceMonitor", func() {
+				It("does not fail when the apply changes nothing", func() {
 					gomock.InOrder(
-						mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).SetArg(2, testServiceMonitor),
+						expectGetResourceVersion(mockClient, testNamespacedName, "1"),
+						expectApply(mockClient, "1", nil),
 					)
 					err := testOcmAgentHandler.ensureServiceMonitor(testconst.Context, testOcmAgent)
 					Expect(err).To(BeNil())
@@ -93,20 +88,17 @@ var _ = Describe("OCM Agent ServiceMonitor Handler", func() {
 		})
 		When("the OCM Agent ServiceMonitor does not already exist", func() {
 			It("creates the ServiceMonitor", func() {
-				notFound := k8serrs.NewNotFound(schema.GroupResource{}, testServiceMonitor.Name)
+				appliedSM := &monitorv1.ServiceMonitor{}
 				gomock.InOrder(
-					mockClient.EXPECT().Get(gomock.Any(), testNamespacedName, gomock.Any()).Times(1).Return(notFound),
-					mockClient.EXPECT().Create(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-						func(ctx context.Context, d *monitorv1.ServiceMonitor, opts ...client.CreateOptions) error {
-							Expect(reflect.DeepEqual(d.Spec, testServiceMonitor.Spec)).To(BeTrue())
-							Expect(d.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
-							Expect(*d.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
-							Expect(*d.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
-							return nil
-						}),
+					expectGetResourceVersion(mockClient, testNamespacedName, ""),
+					expectApply(mockClient, "1", appliedSM),
 				)
 				err := testOcmAgentHandler.ensureServiceMonitor(testconst.Context, testOcmAgent)
 				Expect(err).To(BeNil())
+				Expect(reflect.DeepEqual(appliedSM.Spec, testServiceMonitor.Spec)).To(BeTrue())
+				Expect(appliedSM.ObjectMeta.OwnerReferences[0].Kind).To(Equal("OcmAgent"))
+				Expect(*appliedSM.ObjectMeta.OwnerReferences[0].BlockOwnerDeletion).To(BeTrue())
+				Expect(*appliedSM.ObjectMeta.OwnerReferences[0].Controller).To(BeTrue())
 			})
 		})
 		When("the OCM Agent ServiceMonitor should be removed", func() {
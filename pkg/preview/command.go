@@ -0,0 +1,141 @@
+package preview
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// CommandName is the name of the operator subcommand running the preview
+const CommandName = "preview"
+
+// Run runs the preview subcommand with the given arguments, writing the service logs to stdout and
+// the problems to stderr, and returns the exit code of the subcommand
+func Run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	notificationFile := fs.String("notification", "", "The ManagedNotification or ManagedFleetNotification YAML to preview. "+
+		"The status of a ManagedNotification is used to decide whether its service logs can be sent.")
+	alertsFile := fs.String("alerts", "", "The Alertmanager webhook payload JSON holding the alerts to preview the notification for.")
+	recordFile := fs.String("record", "", "The ManagedFleetNotificationRecord YAML used to decide whether the service logs of a "+
+		"ManagedFleetNotification can be sent. Nothing was sent yet when unset.")
+	name := fs.String("name", "", "The name of the notification of a ManagedNotification to preview. "+
+		"Defaults to the one named by the "+alertLabelNotificationName+" label of each alert.")
+	at := fs.String("at", "", "The RFC 3339 time to decide whether the service logs can be sent at. Defaults to now.")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s --notification <file> --alerts <file> [flags]\n\n", CommandName)
+		fmt.Fprintf(stderr, "Prints the service logs a notification results in for sample alerts, without sending them.\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *notificationFile == "" || *alertsFile == "" {
+		fmt.Fprintln(stderr, "both --notification and --alerts are required")
+		fs.Usage()
+		return 2
+	}
+
+	opts := Options{NotificationName: *name}
+	if *at != "" {
+		t, err := time.Parse(time.RFC3339, *at)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid --at time: %v\n", err)
+			return 2
+		}
+		opts.Clock = fixedClock{t: t}
+	}
+
+	serviceLogs, err := preview(*notificationFile, *alertsFile, *recordFile, opts)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	PrintServiceLogs(stdout, serviceLogs)
+	return 0
+}
+
+// fixedClock is the clock of the --at flag, always at the given time
+type fixedClock struct {
+	t time.Time
+}
+
+// Now returns the time of the clock
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+// Since returns the time elapsed from t to the time of the clock
+func (c fixedClock) Since(t time.Time) time.Duration {
+	return c.t.Sub(t)
+}
+
+// preview reads the files of the subcommand and previews the notification
+func preview(notificationFile, alertsFile, recordFile string, opts Options) ([]ServiceLog, error) {
+	data, err := os.ReadFile(notificationFile)
+	if err != nil {
+		return nil, err
+	}
+	notification, err := DecodeNotification(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", notificationFile, err)
+	}
+
+	data, err = os.ReadFile(alertsFile)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := DecodePayload(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", alertsFile, err)
+	}
+
+	if recordFile != "" {
+		data, err = os.ReadFile(recordFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.Record, err = DecodeRecord(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", recordFile, err)
+		}
+	}
+
+	return Preview(notification, payload, opts)
+}
+
+// PrintServiceLogs writes the service logs in a human readable form
+func PrintServiceLogs(w io.Writer, serviceLogs []ServiceLog) {
+	for i, sl := range serviceLogs {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		status := "resolved"
+		if sl.Firing {
+			status = "firing"
+		}
+		fmt.Fprintf(w, "Alert %d (%s)\n", i+1, status)
+		fmt.Fprintf(w, "  Notification:    %s\n", sl.Notification)
+		fmt.Fprintf(w, "  Severity:        %s\n", sl.Severity)
+		fmt.Fprintf(w, "  Log type:        %s\n", sl.LogType)
+		fmt.Fprintf(w, "  Summary:         %s\n", sl.Summary)
+		fmt.Fprintf(w, "  Body:            %s\n", strings.ReplaceAll(sl.Body, "\n", "\n                   "))
+		for j, ref := range sl.References {
+			label := ""
+			if j == 0 {
+				label = "References:"
+			}
+			fmt.Fprintf(w, "  %-16s %s\n", label, ref)
+		}
+		if sl.LimitedSupport {
+			fmt.Fprintf(w, "  Limited support: %t\n", sl.LimitedSupport)
+		}
+		fmt.Fprintf(w, "  Can be sent:     %t\n", sl.CanBeSent)
+		if !sl.DeferredUntil.IsZero() {
+			fmt.Fprintf(w, "  Deferred until:  %s\n", sl.DeferredUntil.Format(time.RFC3339))
+		}
+	}
+}
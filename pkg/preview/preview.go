@@ -0,0 +1,262 @@
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/clock"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+)
+
+const (
+	// alertLabelNotificationName is the label of an alert naming the notification sent for it
+	alertLabelNotificationName = "managed_notification_template"
+	// alertLabelManagementClusterID is the label of a fleet alert holding the management cluster ID
+	alertLabelManagementClusterID = "_mc_id"
+	// alertLabelHostedClusterID is the label of a fleet alert holding the hosted cluster ID
+	alertLabelHostedClusterID = "_id"
+
+	alertStatusFiring = "firing"
+)
+
+// AlertmanagerPayload is the part of an Alertmanager webhook payload the preview uses
+type AlertmanagerPayload struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// Alert is an alert of an Alertmanager webhook payload
+type Alert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// firing reports whether the alert is firing rather than resolved
+func (a Alert) firing() bool {
+	return a.Status == alertStatusFiring
+}
+
+// templateData returns the alert as the notification templates see it
+func (a Alert) templateData() ocmagentv1alpha1.NotificationTemplateData {
+	return ocmagentv1alpha1.NotificationTemplateData{
+		Labels:      a.Labels,
+		Annotations: a.Annotations,
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+	}
+}
+
+// ServiceLog is the service log a notification results in for an alert, and whether it can be sent
+type ServiceLog struct {
+	Notification   string
+	Firing         bool
+	Severity       ocmagentv1alpha1.NotificationSeverity
+	LogType        string
+	Summary        string
+	Body           string
+	References     []ocmagentv1alpha1.NotificationReferenceType
+	LimitedSupport bool
+	CanBeSent      bool
+	// DeferredUntil is set when the service log is due outside the delivery schedule of the notification
+	DeferredUntil time.Time
+}
+
+// Options tells what the notification is previewed against
+type Options struct {
+	// NotificationName is the name of the notification of a ManagedNotification to preview. Defaults to
+	// the notification named by the managed_notification_template label of each alert.
+	NotificationName string
+	// Record holds what was sent for a ManagedFleetNotification. Nothing was sent yet when it is nil.
+	Record *ocmagentv1alpha1.ManagedFleetNotificationRecord
+	// Clock tells the time the service logs are decided at. Defaults to the real clock.
+	Clock clock.PassiveClock
+}
+
+// Preview renders the service log a ManagedNotification or ManagedFleetNotification results in for each
+// alert of the payload, and decides whether it can be sent against the status of the ManagedNotification
+// or the record of the ManagedFleetNotification, the way the OCM Agent does. A notification the webhooks
+// would reject is reported as an error instead.
+func Preview(notification runtime.Object, payload AlertmanagerPayload, opts Options) ([]ServiceLog, error) {
+	var errs field.ErrorList
+	switch n := notification.(type) {
+	case *ocmagentv1alpha1.ManagedNotification:
+		errs = n.Validate()
+	case *ocmagentv1alpha1.ManagedFleetNotification:
+		errs = n.Validate()
+	default:
+		return nil, fmt.Errorf("cannot preview a %T, only ManagedNotifications and ManagedFleetNotifications", notification)
+	}
+	// The webhooks reject an invalid notification, so it is reported rather than rendered
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid notification: %w", errs.ToAggregate())
+	}
+
+	clk := opts.Clock
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+
+	serviceLogs := make([]ServiceLog, 0, len(payload.Alerts))
+	for i, alert := range payload.Alerts {
+		var sl ServiceLog
+		var err error
+		switch n := notification.(type) {
+		case *ocmagentv1alpha1.ManagedNotification:
+			sl, err = previewManagedNotification(n, alert, opts.NotificationName, clk)
+		case *ocmagentv1alpha1.ManagedFleetNotification:
+			sl, err = previewFleetNotification(n, alert, opts.Record, clk)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("alert %d: %w", i+1, err)
+		}
+		serviceLogs = append(serviceLogs, sl)
+	}
+	return serviceLogs, nil
+}
+
+// previewManagedNotification renders the service log of a notification of the ManagedNotification
+// for the alert. An escalated notification is rendered with the escalated severity, summary and body.
+func previewManagedNotification(mn *ocmagentv1alpha1.ManagedNotification, alert Alert, name string, clk clock.PassiveClock) (ServiceLog, error) {
+	if name == "" {
+		name = alert.Labels[alertLabelNotificationName]
+	}
+	if name == "" {
+		return ServiceLog{}, fmt.Errorf("the alert has no %s label, the notification name must be given", alertLabelNotificationName)
+	}
+
+	n, err := mn.EffectiveNotification(name, clk.Now())
+	if err != nil {
+		return ServiceLog{}, err
+	}
+
+	body, err := n.RenderBody(alert.firing(), alert.templateData())
+	if err != nil {
+		return ServiceLog{}, fmt.Errorf("failed to render the body of notification %s: %w", name, err)
+	}
+
+	canBeSent, deferredUntil, err := mn.CanBeSentAt(name, alert.firing(), clk.Now())
+	if err != nil {
+		return ServiceLog{}, err
+	}
+
+	return ServiceLog{
+		Notification:  name,
+		Firing:        alert.firing(),
+		Severity:      n.Severity,
+		LogType:       n.LogType,
+		Summary:       n.Summary,
+		Body:          body,
+		References:    n.References,
+		CanBeSent:     canBeSent,
+		DeferredUntil: deferredUntil,
+	}, nil
+}
+
+// previewFleetNotification renders the service log of the ManagedFleetNotification for the alert of a
+// hosted cluster. Only firing alerts send a service log for a fleet notification.
+func previewFleetNotification(fn *ocmagentv1alpha1.ManagedFleetNotification, alert Alert, record *ocmagentv1alpha1.ManagedFleetNotificationRecord,
+	clk clock.PassiveClock) (ServiceLog, error) {
+	n := fn.Spec.FleetNotification
+	if name, ok := alert.Labels[alertLabelNotificationName]; ok && name != n.Name {
+		return ServiceLog{}, fmt.Errorf("the alert is for notification %s, not %s", name, n.Name)
+	}
+
+	body, err := n.RenderMessage(alert.templateData())
+	if err != nil {
+		return ServiceLog{}, fmt.Errorf("failed to render the message of notification %s: %w", n.Name, err)
+	}
+
+	sl := ServiceLog{
+		Notification:   n.Name,
+		Firing:         alert.firing(),
+		Severity:       n.Severity,
+		LogType:        n.LogType,
+		Summary:        n.Summary,
+		Body:           body,
+		References:     n.References,
+		LimitedSupport: n.LimitedSupport,
+	}
+	if !alert.firing() {
+		return sl, nil
+	}
+
+	mc := alert.Labels[alertLabelManagementClusterID]
+	hc := alert.Labels[alertLabelHostedClusterID]
+	if mc == "" || hc == "" {
+		return ServiceLog{}, fmt.Errorf("the alert needs the %s and %s labels of a fleet alert", alertLabelManagementClusterID, alertLabelHostedClusterID)
+	}
+
+	// The OCM Agent adds the notification to the record of the management cluster before its first send
+	if record == nil {
+		record = &ocmagentv1alpha1.ManagedFleetNotificationRecord{
+			Status: ocmagentv1alpha1.ManagedFleetNotificationRecordStatus{ManagementCluster: mc},
+		}
+	} else {
+		record = record.DeepCopy()
+	}
+	index := ocmagentv1alpha1.NewNotificationRecordIndex(record)
+	if _, ok := index.NotificationRecordByName(n.Name); !ok {
+		index.UpsertNotificationRecordByName(n.Name, n.ResendWait).SetFleetNotificationSettings(n)
+	}
+
+	sl.CanBeSent, err = record.FiringCanBeSentWithClock(clk, mc, n.Name, hc)
+	if err != nil {
+		return ServiceLog{}, err
+	}
+	return sl, nil
+}
+
+// decoder decodes the resources of the ocmagent.managed.openshift.io API from YAML or JSON
+var decoder = func() runtime.Decoder {
+	scheme := runtime.NewScheme()
+	if err := ocmagentv1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	return serializer.NewCodecFactory(scheme).UniversalDeserializer()
+}()
+
+// DecodeNotification decodes a ManagedNotification or ManagedFleetNotification from YAML or JSON
+func DecodeNotification(data []byte) (runtime.Object, error) {
+	obj, gvk, err := decoder.Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch obj.(type) {
+	case *ocmagentv1alpha1.ManagedNotification, *ocmagentv1alpha1.ManagedFleetNotification:
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("expected a ManagedNotification or ManagedFleetNotification, got %s", gvk.Kind)
+	}
+}
+
+// DecodeRecord decodes a ManagedFleetNotificationRecord from YAML or JSON
+func DecodeRecord(data []byte) (*ocmagentv1alpha1.ManagedFleetNotificationRecord, error) {
+	obj, gvk, err := decoder.Decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := obj.(*ocmagentv1alpha1.ManagedFleetNotificationRecord)
+	if !ok {
+		return nil, fmt.Errorf("expected a ManagedFleetNotificationRecord, got %s", gvk.Kind)
+	}
+	return record, nil
+}
+
+// DecodePayload decodes an Alertmanager webhook payload
+func DecodePayload(data []byte) (AlertmanagerPayload, error) {
+	payload := AlertmanagerPayload{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return payload, err
+	}
+	if len(payload.Alerts) == 0 {
+		return payload, fmt.Errorf("the payload has no alerts")
+	}
+	return payload, nil
+}
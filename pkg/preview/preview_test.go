@@ -0,0 +1,282 @@
+package preview_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	ocmagentv1alpha1 "github.com/openshift/ocm-agent-operator/api/v1alpha1"
+	"github.com/openshift/ocm-agent-operator/pkg/preview"
+)
+
+const (
+	testNotificationName  = "test-notification"
+	testManagementCluster = "test-mc"
+	testHostedCluster     = "test-hc"
+)
+
+var _ = Describe("Notification Preview", func() {
+	var (
+		now       time.Time
+		fakeClock *testing.FakeClock
+		payload   preview.AlertmanagerPayload
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, time.October, 14, 12, 30, 0, 0, time.UTC)
+		fakeClock = testing.NewFakeClock(now)
+		payload = preview.AlertmanagerPayload{
+			Alerts: []preview.Alert{
+				{
+					Status: "firing",
+					Labels: map[string]string{
+						"managed_notification_template": testNotificationName,
+						"namespace":                     "my-app",
+						"_mc_id":                        testManagementCluster,
+						"_id":                           testHostedCluster,
+					},
+					StartsAt: now.Add(-time.Hour),
+				},
+			},
+		}
+	})
+
+	Context("Previewing a ManagedNotification", func() {
+		var mn *ocmagentv1alpha1.ManagedNotification
+
+		BeforeEach(func() {
+			mn = &ocmagentv1alpha1.ManagedNotification{
+				Spec: ocmagentv1alpha1.ManagedNotificationSpec{
+					Notifications: []ocmagentv1alpha1.Notification{
+						{
+							Name:         testNotificationName,
+							Summary:      "Test summary",
+							ActiveDesc:   "{{ .Labels.namespace }} is failing",
+							ResolvedDesc: "{{ .Labels.namespace }} recovered",
+							Templated:    true,
+							Severity:     ocmagentv1alpha1.SeverityWarning,
+							LogType:      "test-log-type",
+							References:   []ocmagentv1alpha1.NotificationReferenceType{"https://example.com/kb"},
+							ResendWait:   1,
+						},
+					},
+				},
+			}
+		})
+
+		It("renders the service log of the notification named by the alert", func() {
+			serviceLogs, err := preview.Preview(mn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs).To(Equal([]preview.ServiceLog{
+				{
+					Notification: testNotificationName,
+					Firing:       true,
+					Severity:     ocmagentv1alpha1.SeverityWarning,
+					LogType:      "test-log-type",
+					Summary:      "Test summary",
+					Body:         "my-app is failing",
+					References:   []ocmagentv1alpha1.NotificationReferenceType{"https://example.com/kb"},
+					CanBeSent:    true,
+				},
+			}))
+		})
+
+		It("decides against the status of the ManagedNotification", func() {
+			mn.Status.NotificationRecords = ocmagentv1alpha1.NotificationRecords{
+				{
+					Name:                testNotificationName,
+					ServiceLogSentCount: 1,
+					Conditions: ocmagentv1alpha1.Conditions{
+						{
+							Type:               ocmagentv1alpha1.ConditionServiceLogSent,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+						},
+					},
+				},
+			}
+			serviceLogs, err := preview.Preview(mn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs[0].CanBeSent).To(BeFalse())
+		})
+
+		It("renders the escalated notification and its deferral", func() {
+			mn.Spec.Notifications[0].Escalation = &ocmagentv1alpha1.EscalationPolicy{
+				AfterSends: ptr.To(int32(1)),
+				Severity:   ocmagentv1alpha1.SeverityMajor,
+				Summary:    "Test escalated summary",
+			}
+			mn.Spec.Notifications[0].Schedule = &ocmagentv1alpha1.DeliverySchedule{
+				Windows:        []ocmagentv1alpha1.DeliveryWindow{{Start: "13:30", End: "14:30"}},
+				BypassSeverity: ocmagentv1alpha1.SeverityCritical,
+			}
+			mn.Status.NotificationRecords = ocmagentv1alpha1.NotificationRecords{
+				{Name: testNotificationName, ServiceLogSentCount: 1},
+			}
+			serviceLogs, err := preview.Preview(mn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs[0].Severity).To(Equal(ocmagentv1alpha1.SeverityMajor))
+			Expect(serviceLogs[0].Summary).To(Equal("Test escalated summary"))
+			Expect(serviceLogs[0].CanBeSent).To(BeFalse())
+			Expect(serviceLogs[0].DeferredUntil).To(Equal(now.Add(time.Hour)))
+		})
+
+		It("previews the given notification for alerts without the label", func() {
+			payload.Alerts[0].Labels = map[string]string{"namespace": "other-app"}
+			_, err := preview.Preview(mn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).To(HaveOccurred())
+
+			serviceLogs, err := preview.Preview(mn, payload, preview.Options{NotificationName: testNotificationName, Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs[0].Body).To(Equal("other-app is failing"))
+		})
+
+		It("errors for an invalid ManagedNotification", func() {
+			mn.Spec.Notifications[0].ActiveDesc = `{{ template "nope" }}`
+			_, err := preview.Preview(mn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).To(MatchError(ContainSubstring("invalid notification")))
+		})
+
+		It("errors for a notification the ManagedNotification does not define", func() {
+			payload.Alerts[0].Labels["managed_notification_template"] = "nope"
+			_, err := preview.Preview(mn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).To(MatchError(ContainSubstring("alert 1")))
+		})
+	})
+
+	Context("Previewing a ManagedFleetNotification", func() {
+		var fn *ocmagentv1alpha1.ManagedFleetNotification
+
+		BeforeEach(func() {
+			fn = &ocmagentv1alpha1.ManagedFleetNotification{
+				Spec: ocmagentv1alpha1.ManagedFleetNotificationSpec{
+					FleetNotification: ocmagentv1alpha1.FleetNotification{
+						Name:                testNotificationName,
+						Summary:             "Test summary",
+						NotificationMessage: "Hosted cluster {{ .Labels._id }} needs attention",
+						Templated:           true,
+						Severity:            ocmagentv1alpha1.SeverityInfo,
+						ResendWait:          1,
+						LimitedSupport:      true,
+					},
+				},
+			}
+		})
+
+		It("renders the service log that can be sent when nothing was sent yet", func() {
+			serviceLogs, err := preview.Preview(fn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs).To(HaveLen(1))
+			Expect(serviceLogs[0].Body).To(Equal("Hosted cluster test-hc needs attention"))
+			Expect(serviceLogs[0].LimitedSupport).To(BeTrue())
+			Expect(serviceLogs[0].CanBeSent).To(BeTrue())
+		})
+
+		It("decides against the record", func() {
+			record := ocmagentv1alpha1.NewNotificationRecordShard("test-ns", testManagementCluster, ocmagentv1alpha1.NotificationRecordByName{
+				NotificationName: testNotificationName,
+				ResendWait:       1,
+				NotificationRecordItems: []ocmagentv1alpha1.NotificationRecordItem{
+					{HostedClusterID: testHostedCluster, FiringNotificationSentCount: 1, LastTransitionTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+				},
+			})
+			serviceLogs, err := preview.Preview(fn, payload, preview.Options{Record: record, Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs[0].CanBeSent).To(BeFalse())
+
+			fakeClock.Step(time.Second)
+			serviceLogs, err = preview.Preview(fn, payload, preview.Options{Record: record, Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs[0].CanBeSent).To(BeTrue())
+		})
+
+		It("does not send a service log for a resolved alert", func() {
+			payload.Alerts[0].Status = "resolved"
+			serviceLogs, err := preview.Preview(fn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceLogs[0].CanBeSent).To(BeFalse())
+		})
+
+		It("errors for an invalid ManagedFleetNotification", func() {
+			fn.Spec.FleetNotification.Summary = ""
+			_, err := preview.Preview(fn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).To(MatchError(ContainSubstring("invalid notification")))
+		})
+
+		It("errors for an alert of another notification", func() {
+			payload.Alerts[0].Labels["managed_notification_template"] = "nope"
+			_, err := preview.Preview(fn, payload, preview.Options{Clock: fakeClock})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Running the subcommand", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "preview")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(dir, "notification.yaml"), []byte(`apiVersion: ocmagent.managed.openshift.io/v1alpha1
+kind: ManagedNotification
+metadata:
+  name: test
+spec:
+  notifications:
+  - name: test-notification
+    summary: Test summary
+    activeBody: "{{ .Labels.namespace }} is failing"
+    templated: true
+    severity: Warning
+    resendWait: 1
+`), 0o600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "alerts.json"), []byte(`{"alerts": [{"status": "firing",
+"labels": {"managed_notification_template": "test-notification", "namespace": "my-app"}}]}`), 0o600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("prints the service logs", func() {
+			var stdout, stderr bytes.Buffer
+			code := preview.Run([]string{
+				"--notification", filepath.Join(dir, "notification.yaml"),
+				"--alerts", filepath.Join(dir, "alerts.json"),
+				"--at", "2026-10-14T12:30:00Z",
+			}, &stdout, &stderr)
+			Expect(code).To(Equal(0), stderr.String())
+			Expect(stdout.String()).To(ContainSubstring("Body:            my-app is failing\n"))
+			Expect(stdout.String()).To(ContainSubstring("Can be sent:     true\n"))
+		})
+
+		It("fails without the notification or the alerts", func() {
+			var stdout, stderr bytes.Buffer
+			Expect(preview.Run([]string{"--alerts", filepath.Join(dir, "alerts.json")}, &stdout, &stderr)).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("--notification"))
+		})
+
+		It("fails when the notification is not a notification", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "notification.yaml"), []byte(`apiVersion: ocmagent.managed.openshift.io/v1alpha1
+kind: ManagedFleetNotificationRecord
+metadata:
+  name: test
+`), 0o600)).To(Succeed())
+			var stdout, stderr bytes.Buffer
+			code := preview.Run([]string{
+				"--notification", filepath.Join(dir, "notification.yaml"),
+				"--alerts", filepath.Join(dir, "alerts.json"),
+			}, &stdout, &stderr)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("expected a ManagedNotification or ManagedFleetNotification"))
+		})
+	})
+})
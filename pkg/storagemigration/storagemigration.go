@@ -0,0 +1,119 @@
+package storagemigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	ocmagentv1beta1 "github.com/openshift/ocm-agent-operator/api/v1beta1"
+)
+
+// OcmAgentCRDName is the name of the OcmAgent CustomResourceDefinition
+const OcmAgentCRDName = "ocmagents.ocmagent.managed.openshift.io"
+
+//+kubebuilder:rbac:groups=ocmagent.managed.openshift.io,resources=ocmagents,verbs=list;update
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,resourceNames=ocmagents.ocmagent.managed.openshift.io,verbs=get
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,resourceNames=ocmagents.ocmagent.managed.openshift.io,verbs=patch
+
+// Migrator rewrites every OcmAgent so that it is persisted in the storage version of the CRD,
+// and then records the storage version as the only stored version. Once no object is stored
+// in an older version, that version can safely be removed from the CRD.
+type Migrator struct {
+	Client client.Client
+	Log    logr.Logger
+	// Backoff controls how often a failed migration is retried, e.g. while the conversion
+	// webhook is not yet reachable by the API server
+	Backoff wait.Backoff
+}
+
+var _ manager.Runnable = &Migrator{}
+
+// NewMigrator returns a Migrator using the supplied client, which should not be restricted
+// to the operator namespace
+func NewMigrator(c client.Client) *Migrator {
+	return &Migrator{
+		Client: c,
+		Log:    ctrl.Log.WithName("storagemigration"),
+		Backoff: wait.Backoff{
+			Duration: 5 * time.Second,
+			Factor:   2,
+			Steps:    8,
+			Cap:      5 * time.Minute,
+		},
+	}
+}
+
+// Start implements manager.Runnable. A failed migration is only logged, as it will be
+// retried when the operator is next restarted and does not affect reconciliation.
+func (m *Migrator) Start(ctx context.Context) error {
+	err := wait.ExponentialBackoffWithContext(ctx, m.Backoff, func(ctx context.Context) (bool, error) {
+		if err := m.Migrate(ctx); err != nil {
+			m.Log.Error(err, "failed to migrate OcmAgent storage version, will retry")
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil && ctx.Err() == nil {
+		m.Log.Error(err, "giving up on migrating OcmAgent storage version")
+	}
+	return nil
+}
+
+// Migrate performs a single storage version migration of the OcmAgent CRD
+func (m *Migrator) Migrate(ctx context.Context) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := m.Client.Get(ctx, types.NamespacedName{Name: OcmAgentCRDName}, crd); err != nil {
+		return err
+	}
+
+	storageVersion := getStorageVersion(crd)
+	if storageVersion != ocmagentv1beta1.GroupVersion.Version {
+		m.Log.Info("skipping migration as the CRD does not store the expected version",
+			"storageVersion", storageVersion, "expectedVersion", ocmagentv1beta1.GroupVersion.Version)
+		return nil
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		return nil
+	}
+
+	ocmAgents := &ocmagentv1beta1.OcmAgentList{}
+	if err := m.Client.List(ctx, ocmAgents); err != nil {
+		return err
+	}
+	for i := range ocmAgents.Items {
+		ocmAgent := &ocmAgents.Items[i]
+		// An unchanged update is enough for the API server to persist the object in the storage version,
+		// and is not rejected by the validating webhook even when the object no longer passes validation.
+		// A conflict means the object was written since it was listed, which has the same effect.
+		if err := m.Client.Update(ctx, ocmAgent); err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsConflict(err) {
+			return err
+		}
+		m.Log.V(2).Info("migrated OcmAgent", "namespace", ocmAgent.Namespace, "name", ocmAgent.Name)
+	}
+
+	patch := client.MergeFromWithOptions(crd.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	crd.Status.StoredVersions = []string{storageVersion}
+	if err := m.Client.Status().Patch(ctx, crd, patch); err != nil {
+		return err
+	}
+	m.Log.Info("migrated OcmAgent storage version", "storageVersion", storageVersion, "count", len(ocmAgents.Items))
+	return nil
+}
+
+// getStorageVersion returns the version that the CRD persists objects in
+func getStorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
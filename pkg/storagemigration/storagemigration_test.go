@@ -0,0 +1,119 @@
+package storagemigration_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocmagentv1beta1 "github.com/openshift/ocm-agent-operator/api/v1beta1"
+	testconst "github.com/openshift/ocm-agent-operator/pkg/consts/test/init"
+	"github.com/openshift/ocm-agent-operator/pkg/storagemigration"
+	clientmocks "github.com/openshift/ocm-agent-operator/pkg/util/test/generated/mocks/client"
+)
+
+var _ = Describe("OcmAgent storage migration", func() {
+	var (
+		mockCtrl         *gomock.Controller
+		mockClient       *clientmocks.MockClient
+		mockStatusWriter *clientmocks.MockStatusWriter
+		migrator         *storagemigration.Migrator
+		testCRD          apiextensionsv1.CustomResourceDefinition
+		testOcmAgents    ocmagentv1beta1.OcmAgentList
+		crdName          = types.NamespacedName{Name: storagemigration.OcmAgentCRDName}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockClient = clientmocks.NewMockClient(mockCtrl)
+		mockStatusWriter = clientmocks.NewMockStatusWriter(mockCtrl)
+		migrator = storagemigration.NewMigrator(mockClient)
+		migrator.Log = testconst.Logger
+
+		testCRD = apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: storagemigration.OcmAgentCRDName},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true},
+					{Name: "v1beta1", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				StoredVersions: []string{"v1alpha1", "v1beta1"},
+			},
+		}
+		testOcmAgents = ocmagentv1beta1.OcmAgentList{
+			Items: []ocmagentv1beta1.OcmAgent{
+				{ObjectMeta: metav1.ObjectMeta{Name: "ocm-agent", Namespace: "test-namespace"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "ocm-agent-hypershift", Namespace: "test-namespace"}},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	When("objects are still stored in an older version", func() {
+		It("rewrites every OcmAgent and drops the older stored version", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), crdName, gomock.Any()).SetArg(2, testCRD),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, testOcmAgents),
+				mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Times(2),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, crd *apiextensionsv1.CustomResourceDefinition, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+						Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta1"}))
+						return nil
+					}),
+			)
+			Expect(migrator.Migrate(testconst.Context)).To(Succeed())
+		})
+
+		It("tolerates objects that changed or were removed after being listed", func() {
+			gr := schema.GroupResource{Group: ocmagentv1beta1.GroupVersion.Group, Resource: "ocmagents"}
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), crdName, gomock.Any()).SetArg(2, testCRD),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, testOcmAgents),
+				mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(k8serrors.NewConflict(gr, "ocm-agent", fmt.Errorf("conflict"))),
+				mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(gr, "ocm-agent-hypershift")),
+				mockClient.EXPECT().Status().Return(mockStatusWriter),
+				mockStatusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()),
+			)
+			Expect(migrator.Migrate(testconst.Context)).To(Succeed())
+		})
+
+		It("keeps the stored versions when an object cannot be rewritten", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().Get(gomock.Any(), crdName, gomock.Any()).SetArg(2, testCRD),
+				mockClient.EXPECT().List(gomock.Any(), gomock.Any()).SetArg(1, testOcmAgents),
+				mockClient.EXPECT().Update(gomock.Any(), gomock.Any()).Return(fmt.Errorf("conversion webhook unavailable")),
+			)
+			Expect(migrator.Migrate(testconst.Context)).To(HaveOccurred())
+		})
+	})
+
+	When("the migration has already completed", func() {
+		It("does nothing", func() {
+			testCRD.Status.StoredVersions = []string{"v1beta1"}
+			mockClient.EXPECT().Get(gomock.Any(), crdName, gomock.Any()).SetArg(2, testCRD)
+			Expect(migrator.Migrate(testconst.Context)).To(Succeed())
+		})
+	})
+
+	When("the CRD does not store the expected version", func() {
+		It("does nothing", func() {
+			testCRD.Spec.Versions[0].Storage = true
+			testCRD.Spec.Versions[1].Storage = false
+			mockClient.EXPECT().Get(gomock.Any(), crdName, gomock.Any()).SetArg(2, testCRD)
+			Expect(migrator.Migrate(testconst.Context)).To(Succeed())
+		})
+	})
+})